@@ -0,0 +1,350 @@
+package zcal
+
+import (
+	"math"
+	"strings"
+	"sync"
+)
+
+// Calendar is a calendar system which counts days in years, months and days.
+// The dates are not checked by ToJD and DaysInMonth, see Validate.
+type Calendar interface {
+	// Name returns the name of the calendar, e.g. "Gonghe" and "Lunar".
+	Name() string
+	// FromJD converts Julian date to the date of the calendar.
+	FromJD(jd float64) (year, month, day int)
+	// ToJD converts the date of the calendar to the Julian date at the
+	// beginning of the day.
+	ToJD(year, month, day int) float64
+	// Validate checks the date, the error is a *DateError.
+	Validate(year, month, day int) error
+	// DaysInMonth returns the number of days of the month.
+	DaysInMonth(year, month int) int
+	// MonthsInYear returns the number of months of the year.
+	MonthsInYear(year int) int
+	// IsLeapYear returns true if year is a leap year, i.e. it has a leap day
+	// or a leap month.
+	IsLeapYear(year int) bool
+}
+
+// GregorianCalendar is the proleptic Gregorian calendar in astronomical year
+// numbering, see GregorianCalendarToJD.
+type GregorianCalendar struct{}
+
+// JulianCalendar is the proleptic Julian calendar in astronomical year
+// numbering, see JulianCalendarToJD.
+type JulianCalendar struct{}
+
+// GongheCalendar is the Gonghe calendar with 4/100/500 leap rule, see
+// GongheDate.
+type GongheCalendar struct{}
+
+// GHCCalendar is the Gonghe calendar with 4/128 leap rule, see GHCDate.
+type GHCCalendar struct{}
+
+// LunarCalendar is the Chinese lunar calendar, see JDToChineseLunar.
+//
+// The months are ordinal, i.e. they are numbered from 1 to 12, or to 13 in
+// a year with a leap month, and the leap month follows the common month of
+// the same name. E.g. 2017 has 閏六月, whose ordinal month is 7, and 十二月
+// is 13. See Month and Ordinal.
+type LunarCalendar struct {
+	// Ephemeris for the lunar calendar, nil means the default one.
+	Ephemeris Ephemeris
+}
+
+// Name returns "Gregorian".
+func (GregorianCalendar) Name() string { return "Gregorian" }
+
+// FromJD converts Julian date to Gregorian calendar date.
+func (GregorianCalendar) FromJD(jd float64) (year, month, day int) {
+	year, month, day, _ = JDToGregorianCalendar(jd)
+	return
+}
+
+// ToJD converts Gregorian calendar date to Julian date.
+func (GregorianCalendar) ToJD(year, month, day int) float64 {
+	return GregorianCalendarToJD(year, month, day)
+}
+
+// Validate checks the Gregorian calendar date.
+func (GregorianCalendar) Validate(year, month, day int) error {
+	return ValidateGregorianCalendar(year, month, day)
+}
+
+// DaysInMonth returns the number of days of the month.
+func (GregorianCalendar) DaysInMonth(year, month int) int {
+	return daysInWesternMonth(month, LeapYearGregorian(year))
+}
+
+// MonthsInYear returns 12.
+func (GregorianCalendar) MonthsInYear(year int) int { return 12 }
+
+// IsLeapYear returns true if year is a leap year.
+func (GregorianCalendar) IsLeapYear(year int) bool { return LeapYearGregorian(year) }
+
+// Name returns "Julian".
+func (JulianCalendar) Name() string { return "Julian" }
+
+// FromJD converts Julian date to Julian calendar date.
+func (JulianCalendar) FromJD(jd float64) (year, month, day int) {
+	year, month, day, _ = JDToJulianCalendar(jd)
+	return
+}
+
+// ToJD converts Julian calendar date to Julian date.
+func (JulianCalendar) ToJD(year, month, day int) float64 {
+	return JulianCalendarToJD(year, month, day)
+}
+
+// Validate checks the Julian calendar date.
+func (JulianCalendar) Validate(year, month, day int) error {
+	return ValidateJulianCalendar(year, month, day)
+}
+
+// DaysInMonth returns the number of days of the month.
+func (JulianCalendar) DaysInMonth(year, month int) int {
+	return daysInWesternMonth(month, LeapYearJulian(year))
+}
+
+// MonthsInYear returns 12.
+func (JulianCalendar) MonthsInYear(year int) int { return 12 }
+
+// IsLeapYear returns true if year is a leap year.
+func (JulianCalendar) IsLeapYear(year int) bool { return LeapYearJulian(year) }
+
+// Name returns "Western".
+func (w WesternCalendar) Name() string { return "Western" }
+
+// DaysInMonth returns the number of days of the month, the month of the reform
+// is shorter, e.g. 1582-10 has 21 days in DefaultWesternCalendar.
+func (w WesternCalendar) DaysInMonth(year, month int) int {
+	y, m := year, month+1
+	if m > 12 {
+		y, m = y+1, 1
+		if y == 0 {
+			y = 1
+		}
+	}
+	return int(w.ToJD(y, m, 1) - w.ToJD(year, month, 1))
+}
+
+// MonthsInYear returns 12.
+func (w WesternCalendar) MonthsInYear(year int) int { return 12 }
+
+// Name returns "Gonghe".
+func (GongheCalendar) Name() string { return "Gonghe" }
+
+// FromJD converts Julian date to Gonghe calendar date.
+func (GongheCalendar) FromJD(jd float64) (year, month, day int) {
+	year, month, day, _ = JDToGongheCalendar(jd)
+	return
+}
+
+// ToJD converts Gonghe calendar date to Julian date.
+func (GongheCalendar) ToJD(year, month, day int) float64 {
+	return GongheCalendarToJD(year, month, day)
+}
+
+// Validate checks the Gonghe calendar date.
+func (GongheCalendar) Validate(year, month, day int) error {
+	return ValidateGongheCalendar(year, month, day)
+}
+
+// DaysInMonth returns the number of days of the month.
+func (GongheCalendar) DaysInMonth(year, month int) int {
+	return daysInGongheMonth(month, LeapYearGonghe(year))
+}
+
+// MonthsInYear returns 12.
+func (GongheCalendar) MonthsInYear(year int) int { return 12 }
+
+// IsLeapYear returns true if year is a leap year.
+func (GongheCalendar) IsLeapYear(year int) bool { return LeapYearGonghe(year) }
+
+// Name returns "GHC".
+func (GHCCalendar) Name() string { return "GHC" }
+
+// FromJD converts Julian date to GHC date.
+func (GHCCalendar) FromJD(jd float64) (year, month, day int) {
+	year, month, day, _ = JDToGHC(jd)
+	return
+}
+
+// ToJD converts GHC date to Julian date.
+func (GHCCalendar) ToJD(year, month, day int) float64 {
+	return GHCToJD(year, month, day)
+}
+
+// Validate checks the GHC date.
+func (GHCCalendar) Validate(year, month, day int) error {
+	return ValidateGHC(year, month, day)
+}
+
+// DaysInMonth returns the number of days of the month.
+func (GHCCalendar) DaysInMonth(year, month int) int {
+	return daysInGongheMonth(month, LeapYearGHC(year+1))
+}
+
+// MonthsInYear returns 12.
+func (GHCCalendar) MonthsInYear(year int) int { return 12 }
+
+// IsLeapYear returns true if year is a leap year.
+func (GHCCalendar) IsLeapYear(year int) bool { return LeapYearGHC(year + 1) }
+
+// Name returns "Lunar".
+func (LunarCalendar) Name() string { return "Lunar" }
+
+// FromJD converts Julian date to Chinese lunar calendar date of ordinal
+// month.
+func (l LunarCalendar) FromJD(jd float64) (year, month, day int) {
+	y, m, leap, d := JDToChineseLunar(l.Ephemeris, jd)
+	return y, l.Ordinal(y, m, leap), d
+}
+
+// ToJD converts Chinese lunar calendar date of ordinal month to Julian date.
+// The months out of the year are counted into the previous or next years,
+// and NaN is returned if they are out of the supported years, see MinYear and
+// MaxYear.
+func (l LunarCalendar) ToJD(year, month, day int) float64 {
+	months := lunarYear(l.Ephemeris, year)
+	for len(months) > 0 && month > len(months) {
+		month -= len(months)
+		year++
+		months = lunarYear(l.Ephemeris, year)
+	}
+	for len(months) > 0 && month < 1 {
+		year--
+		months = lunarYear(l.Ephemeris, year)
+		month += len(months)
+	}
+	if len(months) == 0 {
+		return math.NaN()
+	}
+	return months[month-1].jd + float64(day-1)
+}
+
+// Validate checks the Chinese lunar calendar date of ordinal month.
+func (l LunarCalendar) Validate(year, month, day int) error {
+	n := l.MonthsInYear(year)
+	if month < 1 || month > n {
+		return &DateError{"Lunar", year, month, day, ErrInvalidMonth}
+	}
+	if day < 1 || day > l.DaysInMonth(year, month) {
+		return &DateError{"Lunar", year, month, day, ErrInvalidDay}
+	}
+	return nil
+}
+
+// DaysInMonth returns the number of days (29 or 30) of the ordinal month, or
+// 0 if the month does not exist in the year.
+func (l LunarCalendar) DaysInMonth(year, month int) int {
+	months := lunarYear(l.Ephemeris, year)
+	if month < 1 || month > len(months) {
+		return 0
+	}
+	return months[month-1].days
+}
+
+// MonthsInYear returns 13 if the year has a leap month, or 12.
+func (l LunarCalendar) MonthsInYear(year int) int {
+	return len(lunarYear(l.Ephemeris, year))
+}
+
+// IsLeapYear returns true if the year has a leap month.
+func (l LunarCalendar) IsLeapYear(year int) bool {
+	return l.MonthsInYear(year) == 13
+}
+
+// Month returns the month and whether it is a leap month of the ordinal
+// month, e.g. 7 of 2017 is 閏六月.
+func (l LunarCalendar) Month(year, ordinal int) (month int, isLeap bool) {
+	months := lunarYear(l.Ephemeris, year)
+	if ordinal < 1 || ordinal > len(months) {
+		return 0, false
+	}
+	return months[ordinal-1].month, months[ordinal-1].leap
+}
+
+// Ordinal returns the ordinal month of the month, or 0 if the month does not
+// exist in the year.
+func (l LunarCalendar) Ordinal(year, month int, isLeap bool) int {
+	for i, m := range lunarYear(l.Ephemeris, year) {
+		if m.month == month && m.leap == isLeap {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func daysInWesternMonth(m int, leap bool) int {
+	if m < 1 || m > 12 {
+		return 0
+	}
+	if m == 2 && leap {
+		return 29
+	}
+	return daysOfMonth[m-1]
+}
+
+var calendarRegistry = struct {
+	sync.Mutex
+	names []string
+	m     map[string]Calendar
+}{m: make(map[string]Calendar)}
+
+func init() {
+	for _, c := range []Calendar{
+		DefaultWesternCalendar,
+		GregorianCalendar{},
+		JulianCalendar{},
+		GongheCalendar{},
+		GHCCalendar{},
+		LunarCalendar{},
+	} {
+		RegisterCalendar(c)
+	}
+}
+
+// RegisterCalendar registers the calendar by its name, which replaces the
+// registered one of the same name. The names are case insensitive.
+func RegisterCalendar(c Calendar) {
+	key := strings.ToLower(c.Name())
+	calendarRegistry.Lock()
+	defer calendarRegistry.Unlock()
+	if _, ok := calendarRegistry.m[key]; !ok {
+		calendarRegistry.names = append(calendarRegistry.names, c.Name())
+	}
+	calendarRegistry.m[key] = c
+}
+
+// CalendarByName returns the registered calendar of the name, case
+// insensitive, e.g. "gonghe" and "Lunar". The second return value is false
+// if the name is unknown.
+//
+// The calendars registered by default are Western (DefaultWesternCalendar),
+// Gregorian, Julian, Gonghe, GHC and Lunar (with the default ephemeris).
+func CalendarByName(name string) (Calendar, bool) {
+	calendarRegistry.Lock()
+	defer calendarRegistry.Unlock()
+	c, ok := calendarRegistry.m[strings.ToLower(name)]
+	return c, ok
+}
+
+// CalendarNames returns the names of the registered calendars in the order of
+// registration.
+func CalendarNames() []string {
+	calendarRegistry.Lock()
+	defer calendarRegistry.Unlock()
+	return append([]string(nil), calendarRegistry.names...)
+}
+
+// ConvertDate converts the date of calendar from to the date of calendar to.
+// An error is returned if the date is invalid in calendar from.
+func ConvertDate(from, to Calendar, y, m, d int) (year, month, day int, err error) {
+	if err = from.Validate(y, m, d); err != nil {
+		return
+	}
+	year, month, day = to.FromJD(from.ToJD(y, m, d))
+	return
+}
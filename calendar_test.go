@@ -0,0 +1,97 @@
+package zcal_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/tzengyuxio/zcal"
+)
+
+func TestCalendarRoundTrip(t *testing.T) {
+	for _, name := range CalendarNames() {
+		c, ok := CalendarByName(name)
+		assert.True(t, ok)
+		assert.Equal(t, name, c.Name())
+		for jd := GregorianCalendarToJD(1990, 1, 1); jd < GregorianCalendarToJD(2030, 1, 1); jd += 7 {
+			y, m, d := c.FromJD(jd)
+			assert.NoError(t, c.Validate(y, m, d), "For %s %v", name, jd)
+			assert.Equal(t, jd, c.ToJD(y, m, d), "For %s %d-%d-%d", name, y, m, d)
+		}
+	}
+}
+
+func TestCalendarMonths(t *testing.T) {
+	for _, pair := range []struct {
+		name        string
+		year, month int
+		months      int
+		days        int
+		leap        bool
+	}{
+		{"Western", 1582, 10, 12, 21, false},
+		{"Western", 1700, 2, 12, 28, false},
+		{"Julian", 1700, 2, 12, 29, true},
+		{"Gregorian", 2000, 2, 12, 29, true},
+		{"Gonghe", 2858, 12, 12, 30, false},
+		{"Gonghe", 2860, 12, 12, 31, true},
+		{"Gonghe", 2860, 11, 12, 30, true},
+		{"GHC", 2859, 12, 12, 31, true},
+		{"Lunar", 2017, 7, 13, 30, true}, // 閏六月
+		{"Lunar", 2018, 12, 12, 30, false},
+	} {
+		c, _ := CalendarByName(pair.name)
+		assert.Equal(t, pair.months, c.MonthsInYear(pair.year), "For %s %d", pair.name, pair.year)
+		assert.Equal(t, pair.days, c.DaysInMonth(pair.year, pair.month), "For %s %d-%d", pair.name, pair.year, pair.month)
+		assert.Equal(t, pair.leap, c.IsLeapYear(pair.year), "For %s %d", pair.name, pair.year)
+	}
+}
+
+func TestLunarCalendarOrdinal(t *testing.T) {
+	l := LunarCalendar{}
+	assert.Equal(t, 7, l.Ordinal(2017, 6, true))
+	assert.Equal(t, 13, l.Ordinal(2017, 12, false))
+	assert.Equal(t, 0, l.Ordinal(2018, 6, true))
+	m, leap := l.Month(2017, 7)
+	assert.Equal(t, 6, m)
+	assert.True(t, leap)
+
+	y, m, d := l.FromJD(GregorianCalendarToJD(2017, 7, 23))
+	assert.Equal(t, [3]int{2017, 7, 1}, [3]int{y, m, d})
+	assert.Equal(t, l.ToJD(2018, 1, 1), l.ToJD(2017, 14, 1))
+	assert.Equal(t, l.ToJD(2016, 12, 1), l.ToJD(2017, 0, 1))
+
+	// out of the supported years
+	assert.True(t, math.IsNaN(l.ToJD(30000, 1, 1)))
+	assert.True(t, math.IsNaN(l.ToJD(MinYear-1, 0, 1)))
+	assert.True(t, math.IsNaN(l.ToJD(MaxYear, 100, 1)))
+	assert.False(t, math.IsNaN(l.ToJD(MaxYear, 13, 1)))
+}
+
+func TestConvertDate(t *testing.T) {
+	western, _ := CalendarByName("western")
+	gonghe, _ := CalendarByName("GONGHE")
+	lunar, _ := CalendarByName("lunar")
+
+	y, m, d, err := ConvertDate(western, gonghe, 2017, 2, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, [3]int{2858, 1, 3}, [3]int{y, m, d})
+	y, m, d, err = ConvertDate(western, lunar, 2017, 1, 28)
+	assert.NoError(t, err)
+	assert.Equal(t, [3]int{2017, 1, 1}, [3]int{y, m, d})
+	y, m, d, err = ConvertDate(western, ProlepticJulianCalendar, 1582, 10, 15)
+	assert.NoError(t, err)
+	assert.Equal(t, [3]int{1582, 10, 5}, [3]int{y, m, d})
+
+	_, _, _, err = ConvertDate(gonghe, western, 2858, 1, 31)
+	assert.True(t, errors.Is(err, ErrInvalidDay))
+	_, _, _, err = ConvertDate(lunar, western, 2018, 13, 1)
+	assert.True(t, errors.Is(err, ErrInvalidMonth))
+	_, _, _, err = ConvertDate(western, gonghe, 1582, 10, 10)
+	assert.True(t, errors.Is(err, ErrNonexistentDate))
+
+	_, ok := CalendarByName("mayan")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"Western", "Gregorian", "Julian", "Gonghe", "GHC", "Lunar"}, CalendarNames())
+}
@@ -0,0 +1,409 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/tzengyuxio/zcal"
+	"github.com/tzengyuxio/zcal/internal/convert"
+)
+
+// maxBatch is the maximum number of requests in a batch, and maxBatchCost is
+// the maximum of the total cost of them, see route.Cost.
+const (
+	maxBatch     = 1000
+	maxBatchCost = 2000
+)
+
+// maxBodySize is the maximum size of the body of a batch request.
+const maxBodySize = 1 << 20
+
+// param is a query parameter of an endpoint.
+type param struct {
+	Name        string
+	Type        string // string, integer, number or boolean
+	Description string
+	Required    bool
+	Default     string
+	Enum        []string
+	// Min and Max are the range of an integer or a number, unless both are
+	// zero.
+	Min, Max float64
+}
+
+// route is an endpoint. Its OpenAPI description is generated from the
+// parameters and the type of the result.
+type route struct {
+	Path    string
+	Summary string
+	Params  []param
+	Result  interface{} // a value of the result type
+	Handle  func(p params) (interface{}, error)
+	// Cost is the relative cost of a request, about 0.1 ms of computation
+	// for each unit, which limits the number of requests in a batch.
+	Cost int
+}
+
+// params are the validated parameters of a request, the absent ones are
+// filled by their default values.
+type params map[string]string
+
+func (p params) int(name string) int {
+	n, _ := strconv.Atoi(p[name])
+	return n
+}
+
+func (p params) float(name string) float64 {
+	f, _ := strconv.ParseFloat(p[name], 64)
+	return f
+}
+
+func (p params) bool(name string) bool {
+	b, _ := strconv.ParseBool(p[name])
+	return b
+}
+
+// year returns the astronomical year of the Western year of the parameter.
+func (p params) year(name string) (int, error) {
+	y := p.int(name)
+	if y == 0 {
+		return 0, fmt.Errorf("parameter %q is year 0, the year before 1 is -1", name)
+	}
+	return convert.AstronomicalYear(y), nil
+}
+
+var (
+	fromParam = param{Name: "from", Type: "string", Description: "calendar of date",
+		Default: "western", Enum: convert.InputCalendars}
+	dateParam = param{Name: "date", Type: "string", Required: true,
+		Description: "date in Y-M-D, a Western year before 1 AD is negative, or a Julian date"}
+	tzParam = param{Name: "tz", Type: "number", Default: "8", Description: "time zone in hours",
+		Min: -12, Max: 14}
+	yearDescription = "in Western year numbering, a year before 1 AD is negative"
+)
+
+// ganzhiResult is the result of /ganzhi.
+type ganzhiResult struct {
+	Year  string `json:"year"`
+	Month string `json:"month"`
+	Day   string `json:"day"`
+	Hour  string `json:"hour,omitempty"`
+}
+
+// solarTermResult is an item of the result of /solarterms.
+type solarTermResult struct {
+	Term      string  `json:"term"`
+	Longitude float64 `json:"longitude"`
+	JD        float64 `json:"jd"`
+	Western   string  `json:"western"`
+	Gonghe    string  `json:"gonghe"`
+}
+
+var routes = []route{
+	{
+		Path:    "/convert",
+		Summary: "Convert a date to other calendars",
+		Params: []param{fromParam, dateParam,
+			{Name: "to", Type: "string", Default: strings.Join(convert.Calendars, ","),
+				Description: "comma separated calendars of " + strings.Join(convert.Calendars, ", ")},
+		},
+		Result: convert.Result{},
+		Handle: func(p params) (interface{}, error) {
+			calendars, err := convert.ParseCalendars(p["to"])
+			if err != nil {
+				return nil, err
+			}
+			jd, err := convert.ParseDate(p["from"], p["date"])
+			if err != nil {
+				return nil, err
+			}
+			return convert.Convert(jd, calendars), nil
+		},
+		Cost: 2,
+	},
+	{
+		Path:    "/ganzhi",
+		Summary: "Stem-branches (四柱) of a date",
+		Params: []param{fromParam, dateParam,
+			{Name: "time", Type: "string", Description: "local time in HH:MM for the hour pillar"},
+			tzParam,
+		},
+		Result: ganzhiResult{},
+		Handle: func(p params) (interface{}, error) {
+			jd, err := convert.ParseDate(p["from"], p["date"])
+			if err != nil {
+				return nil, err
+			}
+			tz := p.float("tz")
+			hasHour := p["time"] != "" || p["from"] == "jd"
+			if p["from"] != "jd" {
+				// the local noon if the time is absent
+				hours := 12.0
+				if t := p["time"]; t != "" {
+					if hours, err = parseClock(t); err != nil {
+						return nil, err
+					}
+				}
+				jd += (hours - tz) / 24
+			}
+			r := zcal.FourPillars(nil, jd, tz, zcal.ZiHourNextDay)
+			g := ganzhiResult{r.Year, r.Month, r.Day, r.Hour}
+			if !hasHour {
+				g.Hour = ""
+			}
+			return g, nil
+		},
+		Cost: 4,
+	},
+	{
+		Path:    "/solarterms",
+		Summary: "The 24 solar terms from 立春 of a year",
+		Params: []param{
+			{Name: "year", Type: "integer", Required: true, Description: "year " + yearDescription,
+				Min: convert.MinYear, Max: convert.MaxYear},
+			tzParam,
+		},
+		Result: []solarTermResult{},
+		Handle: func(p params) (interface{}, error) {
+			year, err := p.year("year")
+			if err != nil {
+				return nil, err
+			}
+			solarTerms, err := zcal.SolarTermsChecked(nil, year, p.float("tz"))
+			if err != nil {
+				return nil, err
+			}
+			var terms []solarTermResult
+			for _, s := range solarTerms {
+				terms = append(terms, solarTermResult{
+					Term:      s.Term.String(),
+					Longitude: s.Term.Longitude(),
+					JD:        s.JD,
+					Western:   convert.Western(s.JD),
+					Gonghe:    s.GongheDate().String(),
+				})
+			}
+			return terms, nil
+		},
+		Cost: 10,
+	},
+	{
+		Path:    "/lunar",
+		Summary: "Convert a date from or to Chinese lunar calendar",
+		Params: []param{
+			fromParam,
+			{Name: "date", Type: "string", Description: dateParam.Description + ", absent if the lunar date is given"},
+			{Name: "year", Type: "integer", Description: "lunar year " + yearDescription,
+				Min: convert.MinYear, Max: convert.MaxYear},
+			{Name: "month", Type: "integer", Description: "lunar month", Min: 1, Max: 12},
+			{Name: "leap", Type: "boolean", Default: "false", Description: "true for the leap month"},
+			{Name: "day", Type: "integer", Description: "lunar day", Min: 1, Max: 30},
+		},
+		Result: convert.Result{},
+		Handle: func(p params) (interface{}, error) {
+			var jd float64
+			var err error
+			if p["date"] != "" {
+				jd, err = convert.ParseDate(p["from"], p["date"])
+			} else if p["year"] != "" && p["month"] != "" && p["day"] != "" {
+				var year int
+				if year, err = p.year("year"); err == nil {
+					jd, err = zcal.ChineseLunarToJD(nil, year, p.int("month"), p.bool("leap"), p.int("day"))
+				}
+			} else {
+				err = errors.New("either date or year, month and day are required")
+			}
+			if err != nil {
+				return nil, err
+			}
+			return convert.Convert(jd, convert.Calendars), nil
+		},
+		Cost: 4,
+	},
+}
+
+func parseClock(s string) (float64, error) {
+	f := strings.Split(s, ":")
+	if len(f) == 2 {
+		h, err1 := strconv.Atoi(f[0])
+		m, err2 := strconv.Atoi(f[1])
+		if err1 == nil && err2 == nil && h >= 0 && h < 24 && m >= 0 && m < 60 {
+			return float64(h) + float64(m)/60, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid time %q", s)
+}
+
+// validate checks the raw parameters and fills the default values.
+func (r route) validate(raw map[string]string) (params, error) {
+	p := make(params)
+	for name := range raw {
+		if r.param(name) == nil {
+			return nil, fmt.Errorf("unknown parameter %q", name)
+		}
+	}
+	for _, d := range r.Params {
+		v, ok := raw[d.Name]
+		if !ok || v == "" {
+			if d.Required {
+				return nil, fmt.Errorf("parameter %q is required", d.Name)
+			}
+			p[d.Name] = d.Default
+			continue
+		}
+		var err error
+		var f float64
+		switch d.Type {
+		case "integer":
+			var n int
+			n, err = strconv.Atoi(v)
+			f = float64(n)
+		case "number":
+			if f, err = strconv.ParseFloat(v, 64); err == nil && (math.IsNaN(f) || math.IsInf(f, 0)) {
+				err = errors.New("not finite")
+			}
+		case "boolean":
+			_, err = strconv.ParseBool(v)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parameter %q is not %s: %q", d.Name, d.Type, v)
+		}
+		if (d.Min != 0 || d.Max != 0) && (f < d.Min || f > d.Max) {
+			return nil, fmt.Errorf("parameter %q is out of range [%v, %v]: %q", d.Name, d.Min, d.Max, v)
+		}
+		if d.Enum != nil && !convert.Contains(d.Enum, v) {
+			return nil, fmt.Errorf("parameter %q is not one of %s: %q", d.Name, strings.Join(d.Enum, ", "), v)
+		}
+		p[d.Name] = v
+	}
+	return p, nil
+}
+
+func (r route) param(name string) *param {
+	for i := range r.Params {
+		if r.Params[i].Name == name {
+			return &r.Params[i]
+		}
+	}
+	return nil
+}
+
+func (r route) call(raw map[string]string) (interface{}, error) {
+	p, err := r.validate(raw)
+	if err != nil {
+		return nil, err
+	}
+	return r.Handle(p)
+}
+
+type errorResult struct {
+	Error string `json:"error"`
+}
+
+// batchItem is a result of a batch, either Result or Error is present.
+type batchItem struct {
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+type batchResult struct {
+	Results []batchItem `json:"results"`
+}
+
+func (r route) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		raw := make(map[string]string)
+		for k, v := range req.URL.Query() {
+			raw[k] = v[len(v)-1]
+		}
+		result, err := r.call(raw)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResult{err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	case http.MethodPost:
+		req.Body = http.MaxBytesReader(w, req.Body, maxBodySize)
+		batch, err := decodeBatch(req, r.maxBatch())
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResult{err.Error()})
+			return
+		}
+		results := make([]batchItem, len(batch))
+		for i, raw := range batch {
+			if results[i].Result, err = r.call(raw); err != nil {
+				results[i].Error = err.Error()
+			}
+		}
+		writeJSON(w, http.StatusOK, batchResult{results})
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeJSON(w, http.StatusMethodNotAllowed, errorResult{"method not allowed"})
+	}
+}
+
+// maxBatch returns the maximum number of the requests of r in a batch.
+func (r route) maxBatch() int {
+	if n := maxBatchCost / r.Cost; n < maxBatch {
+		return n
+	}
+	return maxBatch
+}
+
+// decodeBatch decodes a JSON array of at most max parameter objects, the
+// values could be strings, numbers or booleans.
+func decodeBatch(req *http.Request, max int) ([]map[string]string, error) {
+	var items []map[string]interface{}
+	dec := json.NewDecoder(req.Body)
+	dec.UseNumber()
+	if err := dec.Decode(&items); err != nil {
+		return nil, fmt.Errorf("invalid batch: %v", err)
+	}
+	if len(items) > max {
+		return nil, fmt.Errorf("too many requests in a batch: %d > %d", len(items), max)
+	}
+	batch := make([]map[string]string, len(items))
+	for i, item := range items {
+		batch[i] = make(map[string]string)
+		for k, v := range item {
+			switch v := v.(type) {
+			case string:
+				batch[i][k] = v
+			case json.Number:
+				batch[i][k] = v.String()
+			case bool:
+				batch[i][k] = strconv.FormatBool(v)
+			default:
+				return nil, fmt.Errorf("invalid batch: parameter %q of request %d is not a scalar", k, i)
+			}
+		}
+	}
+	return batch, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.Encode(v)
+}
+
+// newServer returns the handler of all routes and /openapi.json.
+func newServer() http.Handler {
+	mux := http.NewServeMux()
+	for _, r := range routes {
+		mux.Handle(r.Path, r)
+	}
+	spec := openAPI(routes)
+	mux.HandleFunc("/openapi.json", func(w http.ResponseWriter, req *http.Request) {
+		writeJSON(w, http.StatusOK, spec)
+	})
+	return mux
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func request(method, target, body string) (int, map[string]interface{}) {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	w := httptest.NewRecorder()
+	newServer().ServeHTTP(w, req)
+	var v map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &v)
+	return w.Code, v
+}
+
+func TestConvert(t *testing.T) {
+	code, v := request("GET", "/convert?from=western&date=2000-01-01&to=gonghe,jd,lunar", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "2840-11-30", v["gonghe"])
+	assert.Equal(t, 2451544.5, v["jd"])
+	assert.Equal(t, "1999年十一月廿五", v["lunar"].(map[string]interface{})["text"])
+	assert.Len(t, v, 3)
+
+	code, v = request("GET", "/convert?date=-841-02-12&to=gonghe", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string]interface{}{"gonghe": "1-01-01"}, v)
+
+	code, v = request("POST", "/convert", `[
+		{"date": "2000-01-01", "to": "ghc"},
+		{"from": "jd", "date": 2451545, "to": "western"},
+		{"date": "2000-02-30"}
+	]`)
+	assert.Equal(t, http.StatusOK, code)
+	results := v["results"].([]interface{})
+	assert.Len(t, results, 3)
+	assert.Equal(t, map[string]interface{}{"result": map[string]interface{}{"ghc": "2841-11-28"}}, results[0])
+	assert.Equal(t, map[string]interface{}{"result": map[string]interface{}{"western": "2000-01-01"}}, results[1])
+	assert.Contains(t, results[2].(map[string]interface{})["error"], "invalid day")
+}
+
+func TestGanzhi(t *testing.T) {
+	// 2017-02-03 23:34 (UTC+8) is 立春
+	code, v := request("GET", "/ganzhi?date=2017-02-03&time=23:40", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "丁酉", v["year"])
+	assert.Equal(t, "壬寅", v["month"])
+	assert.Equal(t, "壬戌", v["day"]) // 子初換日
+	assert.Equal(t, "庚子", v["hour"])
+
+	code, v = request("GET", "/ganzhi?date=2017-02-03", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string]interface{}{"year": "丙申", "month": "辛丑", "day": "辛酉"}, v)
+}
+
+func TestSolarTerms(t *testing.T) {
+	req := httptest.NewRequest("GET", "/solarterms?year=2017", nil)
+	w := httptest.NewRecorder()
+	newServer().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var terms []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &terms))
+	assert.Len(t, terms, 24)
+	assert.Equal(t, "立春", terms[0]["term"])
+	assert.Equal(t, 315.0, terms[0]["longitude"])
+	assert.Equal(t, "2017-02-03", terms[0]["western"])
+	assert.Equal(t, "2018-01-20", terms[23]["western"])
+
+	// the years are in the Western year numbering
+	req = httptest.NewRequest("GET", "/solarterms?year=-841", nil)
+	w = httptest.NewRecorder()
+	newServer().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &terms))
+	assert.Equal(t, "1-01-01", terms[0]["gonghe"])
+	assert.Equal(t, "-841-02-12", terms[0]["western"])
+}
+
+func TestLunar(t *testing.T) {
+	code, v := request("GET", "/lunar?year=2017&month=6&leap=true&day=1", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "2017-07-23", v["western"])
+
+	code, v = request("GET", "/lunar?date=2017-07-23", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "2017年閏六月初一", v["lunar"].(map[string]interface{})["text"])
+
+	code, _ = request("GET", "/lunar?year=2018&month=6&leap=true&day=1", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = request("GET", "/lunar?year=2018", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// 共和元年立春
+	code, v = request("GET", "/lunar?year=-841&month=1&day=2", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "-841-02-12", v["western"])
+	assert.Equal(t, -841.0, v["lunar"].(map[string]interface{})["year"])
+}
+
+func TestValidation(t *testing.T) {
+	for _, pair := range []struct {
+		method, target, body string
+		code                 int
+	}{
+		{"GET", "/convert", "", http.StatusBadRequest},
+		{"GET", "/convert?date=2000-01-01&from=mayan", "", http.StatusBadRequest},
+		{"GET", "/convert?date=2000-01-01&to=mayan", "", http.StatusBadRequest},
+		{"GET", "/convert?date=2000-01-01&bogus=1", "", http.StatusBadRequest},
+		{"GET", "/convert?date=2000/01/01", "", http.StatusBadRequest},
+		{"GET", "/solarterms?year=x", "", http.StatusBadRequest},
+		{"GET", "/ganzhi?date=2000-01-01&time=25:00", "", http.StatusBadRequest},
+		{"GET", "/lunar?leap=maybe&date=2000-01-01", "", http.StatusBadRequest},
+		{"POST", "/convert", `{"date": "2000-01-01"}`, http.StatusBadRequest},
+		{"POST", "/convert", `[{"date": ["2000-01-01"]}]`, http.StatusBadRequest},
+		{"POST", "/convert", "[" + strings.Repeat(`{},`, maxBatch) + "{}]", http.StatusBadRequest},
+		{"POST", "/solarterms", "[" + strings.Repeat(`{"year": 2017},`, maxBatchCost/10) + `{"year": 2017}]`, http.StatusBadRequest},
+		{"POST", "/convert", `[{"date": "` + strings.Repeat("x", maxBodySize) + `"}]`, http.StatusBadRequest},
+		{"GET", "/solarterms?year=100000", "", http.StatusBadRequest},
+		{"GET", "/solarterms?year=0", "", http.StatusBadRequest},
+		{"GET", "/solarterms?year=2017&tz=NaN", "", http.StatusBadRequest},
+		{"GET", "/solarterms?year=2017&tz=Inf", "", http.StatusBadRequest},
+		{"GET", "/solarterms?year=2017&tz=100", "", http.StatusBadRequest},
+		{"GET", "/convert?from=jd&date=1e12", "", http.StatusBadRequest},
+		{"GET", "/convert?from=jd&date=NaN", "", http.StatusBadRequest},
+		{"GET", "/convert?date=100000-01-01", "", http.StatusBadRequest},
+		{"GET", "/ganzhi?from=jd&date=-1000000", "", http.StatusBadRequest},
+		{"GET", "/lunar?year=100000&month=1&day=1", "", http.StatusBadRequest},
+		{"GET", "/lunar?year=2017&month=13&day=1", "", http.StatusBadRequest},
+		{"DELETE", "/convert", "", http.StatusMethodNotAllowed},
+	} {
+		code, v := request(pair.method, pair.target, pair.body)
+		assert.Equal(t, pair.code, code, "For %s %s", pair.method, pair.target)
+		assert.NotEmpty(t, v["error"], "For %s %s", pair.method, pair.target)
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	code, v := request("GET", "/openapi.json", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "3.0.3", v["openapi"])
+	paths := v["paths"].(map[string]interface{})
+	assert.Len(t, paths, len(routes))
+	for _, r := range routes {
+		get := paths[r.Path].(map[string]interface{})["get"].(map[string]interface{})
+		assert.Equal(t, r.Summary, get["summary"])
+		assert.Len(t, get["parameters"], len(r.Params))
+	}
+
+	convert := paths["/convert"].(map[string]interface{})["get"].(map[string]interface{})
+	schema := convert["responses"].(map[string]interface{})["200"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "string"}, properties["gonghe"])
+	assert.Equal(t, map[string]interface{}{"type": "number"}, properties["jd"])
+	lunar := properties["lunar"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"year", "month", "leap", "day", "text"}, lunar["required"])
+
+	// the batch size is limited by the cost
+	solarTerms := paths["/solarterms"].(map[string]interface{})
+	body := solarTerms["post"].(map[string]interface{})["requestBody"].(map[string]interface{})
+	schema = body["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+	assert.Equal(t, float64(maxBatchCost/10), schema["maxItems"])
+	tz := solarTerms["get"].(map[string]interface{})["parameters"].([]interface{})[1].(map[string]interface{})["schema"]
+	assert.Equal(t, map[string]interface{}{"type": "number", "default": "8", "minimum": -12.0, "maximum": 14.0}, tz)
+}
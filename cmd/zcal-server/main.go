@@ -0,0 +1,36 @@
+// Command zcal-server serves the calendar conversions of zcal as an HTTP JSON
+// API.
+//
+// Usage:
+//
+//	zcal-server [-addr :8080]
+//
+// The endpoints accept the parameters in the query string of a GET request,
+// e.g. /convert?from=western&date=2026-10-17&to=gonghe,lunar. A POST request
+// of the same endpoint takes a JSON array of parameter objects and returns
+// the results of all of them, see /openapi.json for the description and the
+// maximum number of the requests in a batch of each endpoint. The years are
+// in Western year numbering, i.e. a year before 1 AD is negative.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen")
+	flag.Parse()
+	s := &http.Server{
+		Addr:              *addr,
+		Handler:           newServer(),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       time.Minute,
+	}
+	log.Printf("zcal-server listening on %s", *addr)
+	log.Fatal(s.ListenAndServe())
+}
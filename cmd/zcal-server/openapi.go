@@ -0,0 +1,143 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+)
+
+// object is a JSON object of the OpenAPI description.
+type object map[string]interface{}
+
+// openAPI returns the OpenAPI 3 description of the routes.
+func openAPI(routes []route) object {
+	paths := object{}
+	for _, r := range routes {
+		var params []object
+		properties := object{}
+		for _, p := range r.Params {
+			s := paramSchema(p)
+			params = append(params, object{
+				"name":        p.Name,
+				"in":          "query",
+				"required":    p.Required,
+				"description": p.Description,
+				"schema":      s,
+			})
+			properties[p.Name] = s
+		}
+		result := schemaOf(reflect.TypeOf(r.Result))
+		paths[r.Path] = object{
+			"get": object{
+				"summary":    r.Summary,
+				"parameters": params,
+				"responses": object{
+					"200": jsonResponse("OK", result),
+					"400": jsonResponse("Invalid parameters", schemaOf(reflect.TypeOf(errorResult{}))),
+				},
+			},
+			"post": object{
+				"summary": r.Summary + " in batch",
+				"requestBody": object{
+					"required": true,
+					"content": object{"application/json": object{"schema": object{
+						"type":     "array",
+						"maxItems": r.maxBatch(),
+						"items":    object{"type": "object", "properties": properties},
+					}}},
+				},
+				"responses": object{
+					"200": jsonResponse("The results in the order of the requests", object{
+						"type": "object",
+						"properties": object{"results": object{
+							"type": "array",
+							"items": object{"type": "object", "properties": object{
+								"result": result,
+								"error":  object{"type": "string"},
+							}},
+						}},
+					}),
+					"400": jsonResponse("Invalid batch", schemaOf(reflect.TypeOf(errorResult{}))),
+				},
+			},
+		}
+	}
+	return object{
+		"openapi": "3.0.3",
+		"info": object{
+			"title":   "zcal",
+			"version": "1.0.0",
+		},
+		"paths": paths,
+	}
+}
+
+func jsonResponse(description string, schema object) object {
+	return object{
+		"description": description,
+		"content":     object{"application/json": object{"schema": schema}},
+	}
+}
+
+func paramSchema(p param) object {
+	s := object{"type": p.Type}
+	if p.Enum != nil {
+		s["enum"] = p.Enum
+	}
+	if p.Default != "" {
+		s["default"] = p.Default
+	}
+	if p.Min != 0 || p.Max != 0 {
+		s["minimum"], s["maximum"] = p.Min, p.Max
+	}
+	return s
+}
+
+// schemaOf returns the JSON schema of type t by its JSON encoding.
+func schemaOf(t reflect.Type) object {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaOf(t.Elem())
+	case reflect.String:
+		return object{"type": "string"}
+	case reflect.Bool:
+		return object{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return object{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return object{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return object{"type": "array", "items": schemaOf(t.Elem())}
+	case reflect.Struct:
+		properties := object{}
+		var required []string
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			name, opts := f.Name, ""
+			if tag, ok := f.Tag.Lookup("json"); ok {
+				if tag == "-" {
+					continue
+				}
+				if i := strings.Index(tag, ","); i >= 0 {
+					tag, opts = tag[:i], tag[i:]
+				}
+				if tag != "" {
+					name = tag
+				}
+			}
+			properties[name] = schemaOf(f.Type)
+			if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Ptr {
+				required = append(required, name)
+			}
+		}
+		s := object{"type": "object", "properties": properties}
+		if required != nil {
+			s["required"] = required
+		}
+		return s
+	}
+	return object{}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/tzengyuxio/zcal"
+	"github.com/tzengyuxio/zcal/internal/convert"
+)
+
+const calUsage = `usage:
+  zcal cal [--calendar gonghe|western|lunar] [--tz HOURS] [--json] [YEAR [MONTH]]
+
+Without MONTH the whole year is printed, and without YEAR the current month.
+A leap month of lunar calendar is written as L6. A Western or lunar year
+before 1 AD is negative.
+`
+
+// cellWidth is the display width of a day in the grid.
+const cellWidth = 10
+
+var weekdayNames = []string{"日", "一", "二", "三", "四", "五", "六"}
+
+// runCal executes the cal command.
+func runCal(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("zcal cal", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	calendar := fs.String("calendar", "gonghe", "calendar of the grid: gonghe, western or lunar")
+	tz := fs.Float64("tz", 8, "time zone in hours of the solar terms")
+	asJSON := fs.Bool("json", false, "output in JSON")
+	pos, err := parseInterspersed(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(pos) > 2 {
+		fmt.Fprint(stderr, calUsage)
+		return 2
+	}
+
+	grids, err := calGrids(strings.ToLower(*calendar), pos, *tz)
+	if err != nil {
+		printError(stderr, err)
+		return 1
+	}
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(grids); err != nil {
+			printError(stderr, err)
+			return 1
+		}
+		return 0
+	}
+	for i, g := range grids {
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		writeGrid(stdout, g)
+	}
+	return 0
+}
+
+// calGrids returns the grids of the month or the year given by the arguments.
+func calGrids(calendar string, pos []string, tz float64) ([]zcal.MonthGrid, error) {
+	today := nowJD()
+	var year, month int
+	var leap bool
+	switch calendar {
+	case "gonghe":
+		g := zcal.GongheDateFromJD(today)
+		year, month = g.Year, g.Month
+	case "western":
+		year, month, _ = zcal.DefaultWesternCalendar.FromJD(today)
+	case "lunar":
+		l := zcal.LunarDateFromJD(nil, today)
+		year, month, leap = convert.WesternYear(l.Year), l.Month, l.IsLeap
+	default:
+		return nil, fmt.Errorf("unknown calendar %q", calendar)
+	}
+
+	whole := false
+	if len(pos) > 0 {
+		var err error
+		if year, err = strconv.Atoi(pos[0]); err != nil || (year == 0 && calendar == "lunar") {
+			return nil, fmt.Errorf("invalid year %q", pos[0])
+		}
+		whole = len(pos) == 1
+	}
+	if len(pos) > 1 {
+		m := pos[1]
+		leap = calendar == "lunar" && strings.HasPrefix(strings.ToUpper(m), "L")
+		if leap {
+			m = m[1:]
+		}
+		var err error
+		if month, err = strconv.Atoi(m); err != nil {
+			return nil, fmt.Errorf("invalid month %q", pos[1])
+		}
+	}
+
+	grid := func(month int, leap bool) (zcal.MonthGrid, error) {
+		switch calendar {
+		case "gonghe":
+			return zcal.GongheMonthGrid(nil, year, month, tz)
+		case "western":
+			return zcal.WesternMonthGrid(nil, year, month, tz)
+		}
+		g, err := zcal.LunarMonthGrid(nil, convert.AstronomicalYear(year), month, leap)
+		// the year of the arguments
+		g.Year = year
+		return g, err
+	}
+	if !whole {
+		g, err := grid(month, leap)
+		if err != nil {
+			return nil, err
+		}
+		return []zcal.MonthGrid{g}, nil
+	}
+
+	var grids []zcal.MonthGrid
+	leapMonth := 0
+	if calendar == "lunar" {
+		leapMonth = zcal.ChineseLunarLeapMonth(nil, convert.AstronomicalYear(year))
+	}
+	for m := 1; m <= 12; m++ {
+		g, err := grid(m, false)
+		if err != nil {
+			return nil, err
+		}
+		grids = append(grids, g)
+		if m == leapMonth {
+			if g, err = grid(m, true); err != nil {
+				return nil, err
+			}
+			grids = append(grids, g)
+		}
+	}
+	return grids, nil
+}
+
+func nowJD() float64 {
+	y, m, d := now().Date()
+	return zcal.GregorianCalendarToJD(y, int(m), d)
+}
+
+// writeGrid writes the month as weeks of 4 lines: the day with the solar
+// term, the date of the other calendar, the lunar date, and the ganzhi with
+// the moon phase.
+func writeGrid(w io.Writer, g zcal.MonthGrid) {
+	var title string
+	switch g.Calendar {
+	case "Gonghe":
+		title = fmt.Sprintf("共和 %d 年 %d 月", g.Year, g.Month)
+	case "Western":
+		title = fmt.Sprintf("西曆 %d 年 %d 月", g.Year, g.Month)
+	case "Lunar":
+		title = fmt.Sprintf("農曆 %d 年 %s", g.Year, zcal.LunarMonthName(g.Month, g.IsLeap))
+	}
+	fmt.Fprintln(w, strings.Repeat(" ", (7*cellWidth-width(title))/2)+title)
+
+	var b strings.Builder
+	for _, n := range weekdayNames {
+		b.WriteString(pad(n, cellWidth))
+	}
+	fmt.Fprintln(w, strings.TrimRight(b.String(), " "))
+
+	for _, week := range g.Weeks {
+		var lines [4]strings.Builder
+		for _, d := range week {
+			cells := [4]string{}
+			if d.InMonth {
+				cells = dayCells(g.Calendar, d)
+			}
+			for i := range lines {
+				lines[i].WriteString(pad(cells[i], cellWidth))
+			}
+		}
+		for i := range lines {
+			fmt.Fprintln(w, strings.TrimRight(lines[i].String(), " "))
+		}
+	}
+}
+
+func dayCells(calendar string, d zcal.GridDay) (cells [4]string) {
+	lunar := zcal.LunarDayName(d.Lunar.Day)
+	if d.Lunar.Day == 1 {
+		lunar = zcal.LunarMonthName(d.Lunar.Month, d.Lunar.IsLeap)
+	}
+	switch calendar {
+	case "Gonghe":
+		cells[0] = strconv.Itoa(d.Gonghe.Day)
+		cells[1] = fmt.Sprintf("%d/%d", d.Western[1], d.Western[2])
+		cells[2] = lunar
+	case "Western":
+		cells[0] = strconv.Itoa(d.Western[2])
+		cells[1] = fmt.Sprintf("%d/%d", d.Gonghe.Month, d.Gonghe.Day)
+		cells[2] = lunar
+	case "Lunar":
+		cells[0] = zcal.LunarDayName(d.Lunar.Day)
+		cells[1] = fmt.Sprintf("%d/%d", d.Western[1], d.Western[2])
+		cells[2] = fmt.Sprintf("%d/%d", d.Gonghe.Month, d.Gonghe.Day)
+	}
+	if d.HasSolarTerm {
+		cells[0] += " " + d.SolarTerm.String()
+	}
+	cells[3] = d.Ganzhi
+	if d.HasMoonPhase {
+		cells[3] += " " + d.MoonPhase.String()
+	}
+	return
+}
+
+// width returns the display width of s, the East Asian wide characters take
+// 2 columns.
+func width(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x1100 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// pad pads s with spaces to the display width n, s is truncated to keep a
+// space before the next cell if it is too wide.
+func pad(s string, n int) string {
+	for width(s) >= n {
+		r := []rune(s)
+		s = string(r[:len(r)-1])
+	}
+	return s + strings.Repeat(" ", n-width(s))
+}
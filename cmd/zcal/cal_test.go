@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tzengyuxio/zcal"
+)
+
+func TestCal(t *testing.T) {
+	code, out, _ := runArgs("cal", "--calendar", "western", "2017", "2")
+	assert.Equal(t, 0, code)
+	lines := strings.Split(out, "\n")
+	assert.Equal(t, "西曆 2017 年 2 月", strings.TrimSpace(lines[0]))
+	assert.Equal(t, "日        一        二        三        四        五        六", lines[1])
+	assert.Equal(t, "                              1         2         3 立春    4", lines[2])
+	assert.Equal(t, "                              1/1       1/2       1/3       1/4", lines[3])
+	assert.Equal(t, "                              初五      初六      初七      初八", lines[4])
+	assert.Equal(t, "                              己未      庚申      辛酉      壬戌 上弦", lines[5])
+	// 5 weeks of 4 lines
+	assert.Len(t, lines, 2+5*4+1)
+
+	code, out, _ = runArgs("cal", "--calendar=lunar", "2017", "L6")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "農曆 2017 年 閏六月")
+
+	code, out, _ = runArgs("cal", "--calendar=lunar", "2017")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, 13, strings.Count(out, "農曆 2017 年"))
+
+	// the lunar years are in the Western year numbering
+	code, out, _ = runArgs("cal", "--calendar=lunar", "-841", "1")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "農曆 -841 年 正月")
+	assert.Contains(t, out, "2/11")
+
+	code, out, _ = runArgs("cal", "2858")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, 12, strings.Count(out, "共和 2858 年"))
+
+	// a negative year begins at 立春 as well
+	code, out, _ = runArgs("cal", "-3", "1")
+	assert.Equal(t, 0, code)
+	lines = strings.Split(out, "\n")
+	assert.Equal(t, "共和 -3 年 1 月", strings.TrimSpace(lines[0]))
+	assert.Equal(t, "1 立春", strings.TrimSpace(lines[2]))
+	assert.Equal(t, "2", strings.Fields(lines[6])[0])
+	assert.Equal(t, "30", strings.TrimSpace(lines[len(lines)-5]))
+}
+
+func TestCalCells(t *testing.T) {
+	// 2016-01-06 is 十一月廿七 with 小寒 in the lunar grid, and the cells of
+	// the whole years are aligned
+	for _, args := range [][]string{
+		{"cal", "--calendar", "lunar", "2015"},
+		{"cal", "--calendar", "western", "2016"},
+		{"cal", "2858"},
+	} {
+		code, out, _ := runArgs(args...)
+		assert.Equal(t, 0, code)
+		for _, line := range strings.Split(out, "\n") {
+			if strings.Contains(line, "年") {
+				continue
+			}
+			// the rune before each column of the cells is a space
+			before := make(map[int]rune)
+			col, last := 0, ' '
+			for _, r := range line {
+				before[col] = last
+				col += width(string(r))
+				last = r
+			}
+			for c := cellWidth; c < col; c += cellWidth {
+				assert.Equal(t, ' ', before[c], "For %q column %d", line, c)
+			}
+		}
+	}
+
+	assert.Equal(t, "十五 小寒 ", pad("十五 小寒 望", cellWidth))
+	assert.Equal(t, "1         ", pad("1", cellWidth))
+}
+
+func TestCalToday(t *testing.T) {
+	defer func(f func() time.Time) { now = f }(now)
+	now = func() time.Time { return time.Date(2017, 2, 3, 23, 0, 0, 0, time.Local) }
+
+	code, out, _ := runArgs("cal", "--json")
+	assert.Equal(t, 0, code)
+	var grids []zcal.MonthGrid
+	assert.NoError(t, json.Unmarshal([]byte(out), &grids))
+	assert.Len(t, grids, 1)
+	assert.Equal(t, "Gonghe", grids[0].Calendar)
+	assert.Equal(t, [2]int{2858, 1}, [2]int{grids[0].Year, grids[0].Month})
+
+	code, out, _ = runArgs("cal", "--calendar", "lunar")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "農曆 2017 年 正月")
+}
+
+func TestCalErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"cal", "--calendar", "mayan"},
+		{"cal", "x"},
+		{"cal", "2017", "x"},
+		{"cal", "2858", "13"},
+		{"cal", "--calendar", "lunar", "2018", "L6"},
+		{"cal", "1", "2", "3"},
+		{"cal", "--calendar", "lunar", "0", "1"},
+	} {
+		code, out, errOut := runArgs(args...)
+		assert.NotEqual(t, 0, code, "For args %q", args)
+		assert.Empty(t, out, "For args %q", args)
+		assert.NotEmpty(t, errOut, "For args %q", args)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/tzengyuxio/zcal"
+	"github.com/tzengyuxio/zcal/drift"
+)
+
+const driftUsage = `usage:
+  zcal drift [--from YEAR] [--to YEAR] [--term TERM] [--tz HOURS] [--calendars NAMES] [--summary]
+
+The offsets in days from the new years to the solar term are written as CSV.
+TERM is the Chinese name or the index from 立春 (0). NAMES is a comma
+separated list of Gonghe, GHC, 4/100/400, 4 and 33, all of them by default.
+`
+
+// runDrift executes the drift command.
+func runDrift(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("zcal drift", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	from := fs.Int("from", -5000, "first solar year")
+	to := fs.Int("to", 5000, "last solar year")
+	term := fs.String("term", "立春", "solar term compared with the new years")
+	tz := fs.Float64("tz", 8, "time zone in hours of the calendar days")
+	names := fs.String("calendars", "", "comma separated calendars")
+	summary := fs.Bool("summary", false, "output the statistics of the calendars only")
+	// no positional arguments, so the negative years are flag values
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprint(stderr, driftUsage)
+		return 2
+	}
+
+	o := drift.Options{FromYear: *from, ToYear: *to, TZ: *tz}
+	var err error
+	if o.Term, err = parseSolarTerm(*term); err != nil {
+		printError(stderr, err)
+		return 2
+	}
+	calendars := drift.Calendars
+	if *names != "" {
+		calendars = nil
+		for _, name := range strings.Split(*names, ",") {
+			c, ok := drift.CalendarByName(strings.TrimSpace(name))
+			if !ok {
+				fmt.Fprintf(stderr, "zcal: unknown calendar %q\n", name)
+				return 2
+			}
+			calendars = append(calendars, c)
+		}
+	}
+
+	reports, err := drift.Analyze(calendars, o)
+	if err == nil {
+		if *summary {
+			err = drift.WriteSummaryCSV(stdout, reports)
+		} else {
+			err = drift.WriteCSV(stdout, reports)
+		}
+	}
+	if err != nil {
+		printError(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// parseSolarTerm parses the Chinese name or the index of a solar term.
+func parseSolarTerm(s string) (zcal.SolarTerm, error) {
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && n < 24 {
+		return zcal.SolarTerm(n), nil
+	}
+	for i := 0; i < 24; i++ {
+		if zcal.SolarTerm(i).String() == s {
+			return zcal.SolarTerm(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown solar term %q", s)
+}
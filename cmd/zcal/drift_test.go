@@ -0,0 +1,38 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrift(t *testing.T) {
+	code, out, _ := runArgs("drift", "--from", "2017", "--to", "2018", "--calendars", "gonghe,ghc", "--tz", "0")
+	assert.Equal(t, 0, code)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	assert.Len(t, lines, 1+2*2)
+	assert.Equal(t, "calendar,year,calendar_year,new_year_jd,instant_jd,offset_days", lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "Gonghe,2017,2858,2457785.5,"))
+
+	code, out, _ = runArgs("drift", "--from", "-100", "--to", "100", "--term", "春分", "--summary")
+	assert.Equal(t, 0, code)
+	lines = strings.Split(strings.TrimSpace(out), "\n")
+	assert.Len(t, lines, 1+5)
+	assert.True(t, strings.HasPrefix(lines[1], "Gonghe,201,"))
+
+	code, _, _ = runArgs("drift", "--term", "3")
+	assert.Equal(t, 0, code)
+
+	for _, args := range [][]string{
+		{"drift", "--term", "春"},
+		{"drift", "--calendars", "mayan"},
+		{"drift", "2017"},
+	} {
+		code, _, _ = runArgs(args...)
+		assert.Equal(t, 2, code, "For %v", args)
+	}
+	code, _, errOut := runArgs("drift", "--from", "1", "--to", "0")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, errOut, "invalid year range")
+}
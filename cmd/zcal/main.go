@@ -0,0 +1,171 @@
+// Command zcal converts dates between the Western, Gonghe, GHC and Chinese
+// lunar calendars.
+//
+// Usage:
+//
+//	zcal convert [--from western] [--to gonghe,ghc,...] [--json] DATE
+//	zcal today [--to gonghe,ghc,...] [--json]
+//	zcal jd [--to gonghe,ghc,...] [--json] JD
+//	zcal cal [--calendar gonghe|western|lunar] [--tz 8] [--json] [YEAR [MONTH]]
+//	zcal drift [--from -5000] [--to 5000] [--term 立春] [--calendars NAMES] [--summary]
+//
+// The dates are written as Y-M-D, a Western year before 1 AD is negative,
+// e.g. -841-02-12 for 共和元年立春, and so are the years of lunar calendar.
+// The calendars of --from are western, gonghe, ghc and jd, and the ones of
+// --to are western, gonghe, ghc, jd, ganzhi, lunar and weekday.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tzengyuxio/zcal/internal/convert"
+)
+
+// now is replaced by tests.
+var now = time.Now
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+const usage = `usage:
+  zcal convert [--from western|gonghe|ghc|jd] [--to CALENDARS] [--json] DATE
+  zcal today [--to CALENDARS] [--json]
+  zcal jd [--to CALENDARS] [--json] JD
+  zcal cal [--calendar gonghe|western|lunar] [--tz HOURS] [--json] [YEAR [MONTH]]
+  zcal drift [--from YEAR] [--to YEAR] [--term TERM] [--tz HOURS] [--calendars NAMES] [--summary]
+
+CALENDARS is a comma separated list of western, gonghe, ghc, jd, ganzhi, lunar
+and weekday, all of them by default.
+`
+
+// run executes the command line and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	cmd, args := args[0], args[1:]
+	switch cmd {
+	case "cal":
+		return runCal(args, stdout, stderr)
+	case "drift":
+		return runDrift(args, stdout, stderr)
+	}
+
+	fs := flag.NewFlagSet("zcal "+cmd, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	from := fs.String("from", "western", "calendar of the input date")
+	to := fs.String("to", strings.Join(convert.Calendars, ","), "comma separated calendars to output")
+	asJSON := fs.Bool("json", false, "output in JSON")
+	pos, err := parseInterspersed(fs, args)
+	if err != nil {
+		return 2
+	}
+
+	var jd float64
+	switch cmd {
+	case "convert":
+		if len(pos) != 1 {
+			fmt.Fprint(stderr, usage)
+			return 2
+		}
+		jd, err = convert.ParseDate(*from, pos[0])
+	case "today":
+		if len(pos) != 0 {
+			fmt.Fprint(stderr, usage)
+			return 2
+		}
+		jd = nowJD()
+	case "jd":
+		if len(pos) != 1 {
+			fmt.Fprint(stderr, usage)
+			return 2
+		}
+		jd, err = convert.ParseDate("jd", pos[0])
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "zcal: unknown command %q\n%s", cmd, usage)
+		return 2
+	}
+	if err != nil {
+		printError(stderr, err)
+		return 1
+	}
+
+	calendars, err := convert.ParseCalendars(*to)
+	if err != nil {
+		printError(stderr, err)
+		return 2
+	}
+	r := convert.Convert(jd, calendars)
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(r); err != nil {
+			printError(stderr, err)
+			return 1
+		}
+		return 0
+	}
+	writeText(stdout, r, calendars)
+	return 0
+}
+
+// parseInterspersed parses the flags which may be mixed with the positional
+// arguments, and returns the positional arguments. An argument begins with a
+// minus sign and a digit is positional, e.g. a date of negative year.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var pos []string
+	for len(args) > 0 {
+		if isNegativeNumber(args[0]) {
+			pos = append(pos, args[0])
+			args = args[1:]
+			continue
+		}
+		i := 1
+		for i < len(args) && !isNegativeNumber(args[i]) {
+			i++
+		}
+		for seg := args[:i]; len(seg) > 0; seg = seg[1:] {
+			if err := fs.Parse(seg); err != nil {
+				return nil, err
+			}
+			if seg = fs.Args(); len(seg) == 0 {
+				break
+			}
+			pos = append(pos, seg[0])
+		}
+		args = args[i:]
+	}
+	return pos, nil
+}
+
+func isNegativeNumber(s string) bool {
+	return len(s) > 1 && s[0] == '-' && s[1] >= '0' && s[1] <= '9'
+}
+
+// printError prints err with the prefix of the command, unless err has it,
+// e.g. a *zcal.DateError.
+func printError(w io.Writer, err error) {
+	msg := err.Error()
+	if !strings.HasPrefix(msg, "zcal: ") {
+		msg = "zcal: " + msg
+	}
+	fmt.Fprintln(w, msg)
+}
+
+func writeText(w io.Writer, r convert.Result, calendars []string) {
+	for _, c := range calendars {
+		fmt.Fprintf(w, "%-8s %s\n", c, r.Text(c))
+	}
+}
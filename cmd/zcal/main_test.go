@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func runArgs(args ...string) (code int, stdout, stderr string) {
+	var out, err bytes.Buffer
+	code = run(args, &out, &err)
+	return code, out.String(), err.String()
+}
+
+func TestConvert(t *testing.T) {
+	code, out, _ := runArgs("convert", "--from", "western", "2000-01-01", "--to", "gonghe,ghc,jd,ganzhi,lunar,weekday")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, strings.Join([]string{
+		"gonghe   2840-11-30",
+		"ghc      2841-11-28",
+		"jd       2451544.5",
+		"ganzhi   戊午",
+		"lunar    1999年十一月廿五",
+		"weekday  Saturday",
+		"",
+	}, "\n"), out)
+
+	// 共和元年立春
+	code, out, _ = runArgs("convert", "--to", "gonghe", "-841-02-12")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "gonghe   1-01-01\n", out)
+	code, out, _ = runArgs("convert", "--to=western", "--from=gonghe", "1-1-1")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "western  -841-02-12\n", out)
+	code, out, _ = runArgs("convert", "--to=lunar", "-841-02-12")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "lunar    -841年正月初二\n", out)
+
+	code, out, _ = runArgs("convert", "--json", "--to", "western,jd,lunar", "--from", "ghc", "2841-11-28")
+	assert.Equal(t, 0, code)
+	var r map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(out), &r))
+	assert.Equal(t, "2000-01-01", r["western"])
+	assert.Equal(t, 2451544.5, r["jd"])
+	assert.Equal(t, map[string]interface{}{
+		"year": 1999.0, "month": 11.0, "leap": false, "day": 25.0, "text": "1999年十一月廿五",
+	}, r["lunar"])
+	assert.Len(t, r, 3)
+}
+
+func TestJD(t *testing.T) {
+	code, out, _ := runArgs("jd", "2451545", "--to", "western,jd")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "western  2000-01-01\njd       2451545\n", out)
+}
+
+func TestToday(t *testing.T) {
+	defer func(f func() time.Time) { now = f }(now)
+	now = func() time.Time { return time.Date(2017, 2, 3, 23, 0, 0, 0, time.Local) }
+	code, out, _ := runArgs("today", "--to", "western")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "western  2017-02-03\n", out)
+}
+
+func TestErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"unknown"},
+		{"convert"},
+		{"convert", "2000-01-01", "2000-01-02"},
+		{"convert", "--to", "mayan", "2000-01-01"},
+		{"convert", "--from", "mayan", "2000-01-01"},
+		{"convert", "2000-02-30"},
+		{"convert", "1582-10-10"},
+		{"convert", "0-01-01"},
+		{"convert", "2000/01/01"},
+		{"convert", "--from", "gonghe", "2858-13-01"},
+		{"jd", "x"},
+		{"jd", "NaN"},
+		{"jd", "-1000000"},
+		{"jd", "1e12"},
+		{"today", "2000-01-01"},
+		{"convert", "--bogus", "2000-01-01"},
+	} {
+		code, out, errOut := runArgs(args...)
+		assert.NotEqual(t, 0, code, "For args %q", args)
+		assert.Empty(t, out, "For args %q", args)
+		assert.NotEmpty(t, errOut, "For args %q", args)
+		assert.NotContains(t, errOut, "zcal: zcal:", "For args %q", args)
+	}
+}
@@ -0,0 +1,94 @@
+package zcal
+
+import "github.com/soniakeys/meeus/base"
+
+// deltaTTable is the observed ΔT in seconds at the beginning of every other
+// year from deltaTYear0, Meeus Table 10.A extended by the IERS values.
+var deltaTTable = []float64{
+	13.1, 12.5, 12.2, 12.0, 12.0, 12.0, 12.0, 12.0, 12.0, 11.9, // 1800
+	11.6, 11.0, 10.2, 9.2, 8.2, 7.1, 6.2, 5.6, 5.4, 5.3, // 1820
+	5.4, 5.6, 5.9, 6.2, 6.5, 6.8, 7.1, 7.3, 7.5, 7.6, // 1840
+	7.7, 7.3, 6.2, 5.2, 2.7, 1.4, -1.2, -2.8, -3.8, -4.8, // 1860
+	-5.5, -5.3, -5.6, -5.7, -5.9, -6.0, -6.3, -6.5, -6.2, -4.7, // 1880
+	-2.8, -0.1, 2.6, 5.3, 7.7, 10.4, 13.3, 16.0, 18.2, 20.2, // 1900
+	21.1, 22.4, 23.5, 23.8, 24.3, 24.0, 23.9, 23.9, 23.7, 24.0, // 1920
+	24.3, 25.3, 26.2, 27.3, 28.2, 29.1, 30.0, 30.7, 31.4, 32.2, // 1940
+	33.1, 34.0, 35.0, 36.5, 38.3, 40.2, 42.2, 44.5, 46.5, 48.5, // 1960
+	50.5, 52.2, 53.8, 54.9, 55.8, 56.9, 58.3, 60.0, 61.6, 63.0, // 1980
+	63.8, 64.3, 64.6, 64.8, 65.5, 66.1, 66.6, 67.3, 68.1, 69.0, // 2000
+	69.4, 69.3, 69.2, // 2020
+}
+
+const deltaTYear0 = 1800
+
+// deltaTYearN is the last year of deltaTTable.
+var deltaTYearN = float64(deltaTYear0 + 2*(len(deltaTTable)-1))
+
+// DeltaT returns ΔT = TT − UT in seconds at the decimal year y, e.g. 2017.5
+// for the middle of 2017.
+//
+// The observed values are interpolated from 1800 to the last tabulated year,
+// and the polynomial expressions of Espenak and Meeus (NASA, Five Millennium
+// Canon of Solar Eclipses) are used for the other years. The extrapolation
+// into the future is blended into the polynomial of 2005-2050, and the
+// uncertainty of ancient ΔT is hours before 500 BC.
+func DeltaT(y float64) float64 {
+	switch {
+	case y >= deltaTYear0 && y <= deltaTYearN:
+		f := (y - deltaTYear0) / 2
+		i := int(f)
+		if i >= len(deltaTTable)-1 {
+			return deltaTTable[len(deltaTTable)-1]
+		}
+		return deltaTTable[i] + (f-float64(i))*(deltaTTable[i+1]-deltaTTable[i])
+	case y > deltaTYearN && y < 2050:
+		// remove the difference at the end of the table gradually
+		d := deltaTTable[len(deltaTTable)-1] - deltaTPolynomial(deltaTYearN)
+		return deltaTPolynomial(y) + d*(2050-y)/(2050-deltaTYearN)
+	}
+	return deltaTPolynomial(y)
+}
+
+// deltaTPolynomial returns ΔT of the Espenak-Meeus polynomials.
+func deltaTPolynomial(y float64) float64 {
+	switch {
+	case y < -500:
+		u := (y - 1820) / 100
+		return -20 + 32*u*u
+	case y < 500:
+		return base.Horner(y/100, 10583.6, -1014.41, 33.78311, -5.952053, -0.1798452, 0.022174192, 0.0090316521)
+	case y < 1600:
+		return base.Horner((y-1000)/100, 1574.2, -556.01, 71.23472, 0.319781, -0.8503463, -0.005050998, 0.0083572073)
+	case y < 1700:
+		return base.Horner(y-1600, 120, -0.9808, -0.01532, 1.0/7129)
+	case y < 1800:
+		return base.Horner(y-1700, 8.83, 0.1603, -0.0059285, 0.00013336, -1.0/1174000)
+	case y < 1860:
+		return base.Horner(y-1800, 13.72, -0.332447, 0.0068612, 0.0041116, -0.00037436, 0.0000121272, -0.0000001699, 0.000000000875)
+	case y < 1900:
+		return base.Horner(y-1860, 7.62, 0.5737, -0.251754, 0.01680668, -0.0004473624, 1.0/233174)
+	case y < 1920:
+		return base.Horner(y-1900, -2.79, 1.494119, -0.0598939, 0.0061966, -0.000197)
+	case y < 1941:
+		return base.Horner(y-1920, 21.20, 0.84493, -0.076100, 0.0020936)
+	case y < 1961:
+		return base.Horner(y-1950, 29.07, 0.407, -1.0/233, 1.0/2547)
+	case y < 1986:
+		return base.Horner(y-1975, 45.45, 1.067, -1.0/260, -1.0/718)
+	case y < 2005:
+		return base.Horner(y-2000, 63.86, 0.3345, -0.060374, 0.0017275, 0.000651814, 0.00002373599)
+	case y < 2050:
+		return base.Horner(y-2000, 62.92, 0.32217, 0.005589)
+	case y < 2150:
+		u := (y - 1820) / 100
+		return -20 + 32*u*u - 0.5628*(2150-y)
+	}
+	u := (y - 1820) / 100
+	return -20 + 32*u*u
+}
+
+// decimalYear returns the decimal year of Julian date jd, e.g. 2000.0 for
+// J2000.
+func decimalYear(jd float64) float64 {
+	return 2000 + (jd-2451545)/365.25
+}
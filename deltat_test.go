@@ -0,0 +1,63 @@
+package zcal_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/tzengyuxio/zcal"
+)
+
+func TestDeltaT(t *testing.T) {
+	for _, pair := range []struct {
+		year   float64
+		deltaT float64
+		delta  float64
+	}{
+		// Espenak and Meeus, Five Millennium Canon of Solar Eclipses, Table 1
+		{-1000, 25400, 100},
+		{-500, 17190, 20},
+		{0, 10580, 10},
+		{500, 5710, 10},
+		{1000, 1570, 10},
+		{1500, 200, 10},
+		{1600, 120, 1},
+		{1700, 9, 1},
+		// observed values
+		{1800, 13.1, .1},
+		{1900, -2.8, .1},
+		{1950, 29.1, .1},
+		{2000, 63.8, .1},
+		{2017, 68.6, .1},
+		{2024, 69.2, .1},
+		{2100, 203, 1},
+	} {
+		assert.InDelta(t, pair.deltaT, DeltaT(pair.year), pair.delta, "For year %.0f", pair.year)
+	}
+
+	// no jump between the table and the polynomials
+	for _, y := range []float64{1800, 2024, 2050, 2150} {
+		assert.InDelta(t, DeltaT(y-1e-9), DeltaT(y+1e-9), 1, "For year %.0f", y)
+	}
+	for y := -3000.0; y < 3000; y += .5 {
+		assert.InDelta(t, DeltaT(y), DeltaT(y+.5), 50, "For year %.1f", y)
+	}
+}
+
+func TestTimeScales(t *testing.T) {
+	jd := JD(GregorianCalendarToJD(2017, 2, 3))
+	assert.InDelta(t, 68.6/86400, float64(jd.TT())-float64(jd), .1/86400)
+	assert.InDelta(t, float64(jd), float64(jd.TT().UT()), 1e-8)
+	assert.Equal(t, jd, jd.Local(8).UT(8))
+	assert.Equal(t, LocalJD(float64(jd)+.5), jd.Local(12))
+
+	// ΔT is about 6.3 hours at the Gonghe epoch
+	tt := SolarTermTT(nil, -840, LiChun)
+	assert.InDelta(t, 6.3/24, float64(tt)-float64(tt.UT()), .2/24)
+	assert.InDelta(t, float64(tt.UT())+8.0/24, SolarTermJD(nil, -840, LiChun, 8), 1e-9)
+
+	// 立春 2017-02-03 15:34:04 UTC
+	tm, err := SolarTermTT(nil, 2017, LiChun).UT().ToTime(time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, [2]int{15, 34}, [2]int{tm.Hour(), tm.Minute()})
+}
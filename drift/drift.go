@@ -0,0 +1,197 @@
+// Package drift measures how the new years of Gonghe-style calendars drift
+// against the tropical year, i.e. the offsets between the new years and a
+// solar term (立春 by default) computed with the VSOP87 theory.
+//
+// The instants are converted to UT by zcal.DeltaT, whose uncertainty is hours
+// in antiquity. The embedded truncated VSOP87 series is less accurate far
+// from the present epoch, see zcal.LoadVSOP87.
+package drift
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/tzengyuxio/zcal"
+)
+
+// Calendars are the leap rules compared by default. The alternative rules
+// begin at zcal.JDOfGongheFirstDay as Gonghe calendar.
+var Calendars = []zcal.Calendar{
+	zcal.GongheCalendar{},
+	zcal.GHCCalendar{},
+	zcal.NewSolarCalendar("4/100/400", zcal.JDOfGongheFirstDay, zcal.GregorianLeapRule),
+	zcal.NewSolarCalendar("4", zcal.JDOfGongheFirstDay, zcal.JulianLeapRule),
+	zcal.NewSolarCalendar("33", zcal.JDOfGongheFirstDay, zcal.Cycle33LeapRule),
+}
+
+// CalendarByName returns the calendar of Calendars by its name, case
+// insensitive. The second return value is false if the name is unknown.
+func CalendarByName(name string) (zcal.Calendar, bool) {
+	for _, c := range Calendars {
+		if strings.EqualFold(c.Name(), name) {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// Options of an analysis.
+type Options struct {
+	// FromYear and ToYear are the range of solar years, inclusive, in
+	// astronomical year numbering, see zcal.SolarTermJD.
+	FromYear, ToYear int
+	// Term is the solar term which the new years are compared with, the zero
+	// value is 立春.
+	Term zcal.SolarTerm
+	// TZ is the time zone in hours of the calendar days.
+	TZ float64
+	// Ephemeris, nil means the default one.
+	Ephemeris zcal.Ephemeris
+}
+
+// ErrYearRange means FromYear is after ToYear.
+var ErrYearRange = errors.New("drift: invalid year range")
+
+// Sample is the offset of a solar year.
+type Sample struct {
+	Year         int     // the solar year of the term
+	CalendarYear int     // the year of the nearest new year
+	NewYear      float64 // the Julian date of the nearest new year
+	Instant      float64 // the Julian date of the term, local time of TZ
+	Offset       float64 // Instant minus NewYear in days, in (-183, 183]
+}
+
+// Summary is the statistics of the offsets of a calendar, in days.
+type Summary struct {
+	Years            int
+	Mean, StdDev     float64
+	Min, Max         float64
+	MinYear, MaxYear int // the solar years of Min and Max
+	// Trend is the slope of the least squares line of the offsets, in days
+	// per 1000 years.
+	Trend float64
+}
+
+// Report is the result of a calendar.
+type Report struct {
+	Calendar string
+	Samples  []Sample
+	Summary  Summary
+}
+
+// Analyze returns the reports of the calendars in the order of them.
+func Analyze(calendars []zcal.Calendar, o Options) ([]Report, error) {
+	if o.FromYear > o.ToYear {
+		return nil, ErrYearRange
+	}
+	instants := make([]float64, 0, o.ToYear-o.FromYear+1)
+	for y := o.FromYear; y <= o.ToYear; y++ {
+		instants = append(instants, zcal.SolarTermJD(o.Ephemeris, y, o.Term, o.TZ))
+	}
+
+	reports := make([]Report, len(calendars))
+	for i, c := range calendars {
+		samples := make([]Sample, len(instants))
+		for j, instant := range instants {
+			samples[j] = sample(c, o.FromYear+j, instant)
+		}
+		reports[i] = Report{c.Name(), samples, Summarize(samples)}
+	}
+	return reports, nil
+}
+
+// sample returns the sample of the instant, which is compared with the
+// nearest new year of c.
+func sample(c zcal.Calendar, year int, instant float64) Sample {
+	y, _, _ := c.FromJD(instant)
+	newYear := c.ToJD(y, 1, 1)
+	if next := c.ToJD(y+1, 1, 1); next-instant < instant-newYear {
+		y, newYear = y+1, next
+	}
+	return Sample{year, y, newYear, instant, instant - newYear}
+}
+
+// Summarize returns the statistics of the samples.
+func Summarize(samples []Sample) Summary {
+	s := Summary{Years: len(samples)}
+	if len(samples) == 0 {
+		return s
+	}
+	var sumX, sumY float64
+	for i, p := range samples {
+		if i == 0 || p.Offset < s.Min {
+			s.Min, s.MinYear = p.Offset, p.Year
+		}
+		if i == 0 || p.Offset > s.Max {
+			s.Max, s.MaxYear = p.Offset, p.Year
+		}
+		sumX += float64(p.Year)
+		sumY += p.Offset
+	}
+	n := float64(len(samples))
+	s.Mean = sumY / n
+	meanX := sumX / n
+	var sxx, sxy, syy float64
+	for _, p := range samples {
+		dx, dy := float64(p.Year)-meanX, p.Offset-s.Mean
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+	s.StdDev = math.Sqrt(syy / n)
+	if sxx > 0 {
+		s.Trend = sxy / sxx * 1000
+	}
+	return s
+}
+
+// WriteCSV writes the samples of the reports as CSV with a header line.
+func WriteCSV(w io.Writer, reports []Report) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"calendar", "year", "calendar_year", "new_year_jd", "instant_jd", "offset_days"})
+	for _, r := range reports {
+		for _, s := range r.Samples {
+			cw.Write([]string{
+				r.Calendar,
+				strconv.Itoa(s.Year),
+				strconv.Itoa(s.CalendarYear),
+				formatFloat(s.NewYear, 1),
+				formatFloat(s.Instant, 5),
+				formatFloat(s.Offset, 5),
+			})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteSummaryCSV writes the summaries of the reports as CSV with a header
+// line.
+func WriteSummaryCSV(w io.Writer, reports []Report) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"calendar", "years", "mean", "stddev", "min", "min_year", "max", "max_year", "trend_per_1000_years"})
+	for _, r := range reports {
+		s := r.Summary
+		cw.Write([]string{
+			r.Calendar,
+			strconv.Itoa(s.Years),
+			formatFloat(s.Mean, 5),
+			formatFloat(s.StdDev, 5),
+			formatFloat(s.Min, 5),
+			strconv.Itoa(s.MinYear),
+			formatFloat(s.Max, 5),
+			strconv.Itoa(s.MaxYear),
+			formatFloat(s.Trend, 5),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatFloat(f float64, prec int) string {
+	return strconv.FormatFloat(f, 'f', prec, 64)
+}
@@ -0,0 +1,101 @@
+package drift_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tzengyuxio/zcal"
+	. "github.com/tzengyuxio/zcal/drift"
+)
+
+func TestAnalyze(t *testing.T) {
+	gonghe, _ := CalendarByName("gonghe")
+	reports, err := Analyze([]zcal.Calendar{gonghe}, Options{FromYear: 2017, ToYear: 2018})
+	assert.NoError(t, err)
+	assert.Len(t, reports, 1)
+	assert.Equal(t, "Gonghe", reports[0].Calendar)
+
+	// 共和2858年 begins at 2017-02-01, 立春 is at 2017-02-03 15:34 UTC
+	s := reports[0].Samples[0]
+	assert.Equal(t, 2017, s.Year)
+	assert.Equal(t, 2858, s.CalendarYear)
+	assert.Equal(t, zcal.GregorianCalendarToJD(2017, 2, 1), s.NewYear)
+	assert.InDelta(t, 2.649, s.Offset, .001)
+
+	// 春分 is about 47 days after the new year
+	reports, _ = Analyze([]zcal.Calendar{gonghe}, Options{FromYear: 2017, ToYear: 2017, Term: zcal.ChunFen, TZ: 8})
+	assert.InDelta(t, 47, reports[0].Samples[0].Offset, 1)
+	// 秋分 is nearer to the next new year
+	reports, _ = Analyze([]zcal.Calendar{gonghe}, Options{FromYear: 2017, ToYear: 2017, Term: zcal.QiuFen})
+	assert.Equal(t, 2859, reports[0].Samples[0].CalendarYear)
+	assert.True(t, reports[0].Samples[0].Offset < 0)
+
+	// 共和-3年 begins 1461 days before 共和元年
+	reports, _ = Analyze([]zcal.Calendar{gonghe}, Options{FromYear: -844, ToYear: -844})
+	s = reports[0].Samples[0]
+	assert.Equal(t, -3, s.CalendarYear)
+	assert.Equal(t, zcal.JDOfGongheFirstDay-1461, s.NewYear)
+	assert.InDelta(t, 0, s.Offset, 2)
+
+	// the new years are the first days of the calendar years
+	reports, _ = Analyze(Calendars, Options{FromYear: -5000, ToYear: -4000})
+	for i, r := range reports {
+		for _, s := range r.Samples {
+			y, m, d := Calendars[i].FromJD(s.NewYear)
+			if y != s.CalendarYear || m != 1 || d != 1 {
+				t.Fatalf("%s new year of %d is %d-%02d-%02d", r.Calendar, s.Year, y, m, d)
+			}
+		}
+	}
+
+	_, err = Analyze(Calendars, Options{FromYear: 1, ToYear: 0})
+	assert.Equal(t, ErrYearRange, err)
+}
+
+func TestSummary(t *testing.T) {
+	reports, err := Analyze(Calendars, Options{FromYear: -3000, ToYear: 3000})
+	assert.NoError(t, err)
+	trend := make(map[string]float64)
+	for _, r := range reports {
+		assert.Equal(t, 6001, r.Summary.Years)
+		assert.True(t, r.Summary.Min <= r.Summary.Mean && r.Summary.Mean <= r.Summary.Max)
+		trend[r.Calendar] = r.Summary.Trend
+	}
+	// Julian leap rule drifts 3 days in 400 years
+	assert.InDelta(t, -7.5, trend["4"], .5)
+	for _, name := range []string{"Gonghe", "GHC", "4/100/400", "33"} {
+		assert.True(t, math.Abs(trend[name]) < 1, "Trend of %s is %f", name, trend[name])
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	s := Summarize([]Sample{{Year: 0, Offset: 1}, {Year: 1000, Offset: 2}, {Year: 2000, Offset: 3}})
+	assert.Equal(t, Summary{
+		Years: 3, Mean: 2, StdDev: math.Sqrt(2.0 / 3),
+		Min: 1, Max: 3, MinYear: 0, MaxYear: 2000, Trend: 1,
+	}, s)
+	assert.Equal(t, Summary{}, Summarize(nil))
+}
+
+func TestWriteCSV(t *testing.T) {
+	reports, _ := Analyze(Calendars[:2], Options{FromYear: 2017, ToYear: 2018})
+	var b bytes.Buffer
+	assert.NoError(t, WriteCSV(&b, reports))
+	records, err := csv.NewReader(&b).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 1+2*2)
+	assert.Equal(t, []string{"calendar", "year", "calendar_year", "new_year_jd", "instant_jd", "offset_days"}, records[0])
+	assert.Equal(t, []string{"Gonghe", "2017", "2858", "2457785.5"}, records[1][:4])
+	assert.Equal(t, "GHC", records[3][0])
+
+	b.Reset()
+	assert.NoError(t, WriteSummaryCSV(&b, reports))
+	records, err = csv.NewReader(&b).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 3)
+	assert.Equal(t, "trend_per_1000_years", records[0][8])
+	assert.Equal(t, []string{"Gonghe", "2"}, records[1][:2])
+}
@@ -0,0 +1,208 @@
+// Package eclipse predicts the solar and lunar eclipses, with the method of
+// Meeus (Astronomical Algorithms, chapter 54), e.g. to verify the eclipses
+// recorded in Chinese chronicles against the dates of Gonghe calendar.
+//
+// The instants are converted from TT to UT by zcal.DeltaT, whose uncertainty
+// is hours in antiquity, so that the visibility of an ancient eclipse at a
+// location is uncertain as well.
+package eclipse
+
+import (
+	"math"
+	"sort"
+
+	"github.com/soniakeys/meeus/eclipse"
+	"github.com/tzengyuxio/zcal"
+)
+
+// Kind is a solar or lunar eclipse.
+type Kind int
+
+// The kinds of eclipses.
+const (
+	SolarEclipse Kind = iota
+	LunarEclipse
+)
+
+func (k Kind) String() string {
+	if k == LunarEclipse {
+		return "月食"
+	}
+	return "日食"
+}
+
+// Type is the type of an eclipse.
+type Type int
+
+// The types of eclipses, Annular and Hybrid are solar, and Penumbral is
+// lunar.
+const (
+	Partial Type = iota
+	Annular
+	Hybrid // annular-total
+	Total
+	Penumbral
+)
+
+var typeNames = []string{"partial", "annular", "hybrid", "total", "penumbral"}
+
+func (t Type) String() string {
+	return typeNames[t]
+}
+
+// Eclipse is a solar or lunar eclipse.
+type Eclipse struct {
+	Kind     Kind
+	Type     Type
+	Lunation zcal.Lunation // the lunation of the new moon or the full moon
+	// JD is the local time of the greatest eclipse, i.e. UT plus the time
+	// zone offset.
+	JD float64
+	// Magnitude is the fraction of the diameter of the sun covered by the
+	// moon at the greatest eclipse, or the ratio of the apparent diameters of
+	// the moon and the sun for a total or annular eclipse, observed where the
+	// axis of the shadow is nearest to the center of the Earth. For a lunar
+	// eclipse it is the
+	// fraction of the diameter of the moon in the umbra, or in the penumbra
+	// for a penumbral eclipse.
+	Magnitude float64
+	// Gamma is the least distance from the axis of the shadow to the center
+	// of the Earth (solar) or of the moon (lunar) in equatorial Earth radii,
+	// negative for south.
+	Gamma float64
+	// Central is true if the axis of the shadow of a solar eclipse touches
+	// the Earth.
+	Central bool
+
+	// The local times of the beginning and the end of the phases of a lunar
+	// eclipse, zero if the phase does not occur. Solar eclipses depend on the
+	// location, see Observe.
+	PenumbralBegin, PenumbralEnd float64
+	PartialBegin, PartialEnd     float64
+	TotalBegin, TotalEnd         float64
+
+	tz float64
+}
+
+// Date returns the local Western calendar date of the greatest eclipse.
+func (e Eclipse) Date() (year, month, day int) {
+	return zcal.DefaultWesternCalendar.FromJD(e.JD)
+}
+
+// GongheDate returns the local Gonghe calendar date of the greatest eclipse.
+func (e Eclipse) GongheDate() zcal.GongheDate {
+	return zcal.GongheDateFromJD(e.JD)
+}
+
+// List returns the solar and lunar eclipses whose greatest eclipses are from
+// the local Julian date from to before to, in time zone tz hours, ordered by
+// time.
+func List(from, to, tz float64) []Eclipse {
+	eclipses := append(Solar(from, to, tz), Lunar(from, to, tz)...)
+	sort.Slice(eclipses, func(i, j int) bool {
+		return eclipses[i].JD < eclipses[j].JD
+	})
+	return eclipses
+}
+
+// lunations returns the range of lunations around the local Julian dates.
+func lunations(from, to, tz float64) (k0, k1 zcal.Lunation) {
+	return zcal.LunationOf(from-tz/24) - 1, zcal.LunationOf(to-tz/24) + 1
+}
+
+// local returns the local time in tz of jde.
+func local(jde, tz float64) float64 {
+	return float64(zcal.TT(jde).UT().Local(tz))
+}
+
+// Solar returns the solar eclipses, see List.
+func Solar(from, to, tz float64) []Eclipse {
+	var eclipses []Eclipse
+	k0, k1 := lunations(from, to, tz)
+	for k := k0; k <= k1; k++ {
+		t, central, jmax, γ, u, _, mag := eclipse.Solar(2000 + float64(k)/12.3685)
+		if t == eclipse.None {
+			continue
+		}
+		e := Eclipse{Kind: SolarEclipse, Lunation: k, JD: local(jmax, tz), Magnitude: mag, Gamma: γ, Central: central, tz: tz}
+		switch t {
+		case eclipse.Partial:
+			e.Type = Partial
+		case eclipse.Annular:
+			e.Type = Annular
+		case eclipse.AnnularTotal:
+			e.Type = Hybrid
+		case eclipse.Total:
+			e.Type = Total
+			// Meeus reports both types of non-central eclipses as total
+			if !central && u > 0 {
+				e.Type = Annular
+			}
+		}
+		if e.Type != Partial {
+			e.Magnitude = diameterRatio(jmax, γ)
+		}
+		if e.JD >= from && e.JD < to {
+			eclipses = append(eclipses, e)
+		}
+	}
+	return eclipses
+}
+
+// diameterRatio returns the ratio of the apparent diameters of the moon and
+// the sun at jde, observed at the altitude of the moon sin h = √(1-γ²).
+func diameterRatio(jde, γ float64) float64 {
+	_, _, Δ := zcal.ApparentMoonEquatorial(jde)
+	_, _, R := zcal.ApparentSun(nil, jde)
+	sh := math.Sqrt(math.Max(0, 1-γ*γ))
+	return topocentricMoonSemidiameter(Δ, sh) / sunSemidiameter(R)
+}
+
+// sunSemidiameter returns the semidiameter in degrees of the sun at R AU, and
+// moonSemidiameter of the moon at Δ km, see chapter 55 of Meeus.
+func sunSemidiameter(R float64) float64  { return 959.63 / 3600 / R }
+func moonSemidiameter(Δ float64) float64 { return 358473400 / 3600 / Δ }
+
+// topocentricMoonSemidiameter returns the semidiameter of the moon at Δ km
+// observed at altitude h, where sh = sin h. The moon is nearer to the
+// observer when it is higher, see (55.1) p. 390.
+func topocentricMoonSemidiameter(Δ, sh float64) float64 {
+	return moonSemidiameter(Δ) * (1 + sh*earthRadius/Δ)
+}
+
+// earthRadius is the equatorial radius of the Earth in km.
+const earthRadius = 6378.14
+
+// Lunar returns the lunar eclipses, see List.
+func Lunar(from, to, tz float64) []Eclipse {
+	var eclipses []Eclipse
+	k0, k1 := lunations(from, to, tz)
+	for k := k0; k <= k1; k++ {
+		t, jmax, γ, _, _, mag, sdTotal, sdPartial, sdPenumbral := eclipse.Lunar(2000 + (float64(k)+.5)/12.3685)
+		if t == eclipse.None {
+			continue
+		}
+		e := Eclipse{Kind: LunarEclipse, Lunation: k, JD: local(jmax, tz), Magnitude: mag, Gamma: γ, tz: tz}
+		phase := func(sd float64) (float64, float64) {
+			if sd == 0 {
+				return 0, 0
+			}
+			return e.JD - sd, e.JD + sd
+		}
+		e.PenumbralBegin, e.PenumbralEnd = phase(sdPenumbral.Day())
+		e.PartialBegin, e.PartialEnd = phase(sdPartial.Day())
+		e.TotalBegin, e.TotalEnd = phase(sdTotal.Day())
+		switch t {
+		case eclipse.Penumbral:
+			e.Type = Penumbral
+		case eclipse.Umbral:
+			e.Type = Partial
+		default:
+			e.Type = Total
+		}
+		if e.JD >= from && e.JD < to {
+			eclipses = append(eclipses, e)
+		}
+	}
+	return eclipses
+}
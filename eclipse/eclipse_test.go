@@ -0,0 +1,131 @@
+package eclipse_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tzengyuxio/zcal"
+	. "github.com/tzengyuxio/zcal/eclipse"
+)
+
+// clock returns the Julian date of the time of the Gregorian date.
+func clock(y, m, d, hour, min int) float64 {
+	return zcal.GregorianCalendarToJD(y, m, d) + (float64(hour)+float64(min)/60)/24
+}
+
+func TestList(t *testing.T) {
+	// the eclipses of 2017 in UT
+	eclipses := List(zcal.GregorianCalendarToJD(2017, 1, 1), zcal.GregorianCalendarToJD(2018, 1, 1), 0)
+	assert.Len(t, eclipses, 4)
+	for i, pair := range []struct {
+		kind      Kind
+		typ       Type
+		jd        float64
+		magnitude float64
+		lunation  zcal.Lunation
+	}{
+		{LunarEclipse, Penumbral, clock(2017, 2, 11, 0, 44), .988, 211},
+		{SolarEclipse, Annular, clock(2017, 2, 26, 14, 53), .992, 212},
+		{LunarEclipse, Partial, clock(2017, 8, 7, 18, 21), .246, 217},
+		{SolarEclipse, Total, clock(2017, 8, 21, 18, 26), 1.031, 218},
+	} {
+		e := eclipses[i]
+		assert.Equal(t, pair.kind, e.Kind)
+		assert.Equal(t, pair.typ, e.Type, "For %v", e)
+		assert.InDelta(t, pair.jd, e.JD, 2.0/1440, "For %v", e)
+		assert.InDelta(t, pair.magnitude, e.Magnitude, .01, "For %v", e)
+		assert.Equal(t, pair.lunation, e.Lunation)
+	}
+	assert.True(t, eclipses[3].Central)
+	assert.InDelta(t, .4367, eclipses[3].Gamma, .001)
+	assert.Equal(t, "日食", eclipses[3].Kind.String())
+	assert.Equal(t, "total", eclipses[3].Type.String())
+
+	// 2018-2020
+	assert.Len(t, Solar(zcal.GregorianCalendarToJD(2018, 1, 1), zcal.GregorianCalendarToJD(2021, 1, 1), 0), 8)
+	assert.Len(t, Lunar(zcal.GregorianCalendarToJD(2018, 1, 1), zcal.GregorianCalendarToJD(2021, 1, 1), 0), 8)
+	assert.Empty(t, List(zcal.GregorianCalendarToJD(2017, 3, 1), zcal.GregorianCalendarToJD(2017, 8, 1), 0))
+}
+
+func TestLunarPhases(t *testing.T) {
+	// the total lunar eclipse of 2018-01-31, UTC+8
+	eclipses := Lunar(zcal.GregorianCalendarToJD(2018, 1, 1), zcal.GregorianCalendarToJD(2018, 2, 1), 8)
+	assert.Len(t, eclipses, 1)
+	e := eclipses[0]
+	assert.Equal(t, Total, e.Type)
+	assert.InDelta(t, 1.316, e.Magnitude, .005)
+	for _, pair := range [][2]float64{
+		{clock(2018, 1, 31, 18, 51), e.PenumbralBegin},
+		{clock(2018, 1, 31, 19, 48), e.PartialBegin},
+		{clock(2018, 1, 31, 20, 52), e.TotalBegin},
+		{clock(2018, 1, 31, 21, 30), e.JD},
+		{clock(2018, 1, 31, 22, 8), e.TotalEnd},
+		{clock(2018, 1, 31, 23, 11), e.PartialEnd},
+		{clock(2018, 2, 1, 0, 8), e.PenumbralEnd},
+	} {
+		assert.InDelta(t, pair[0], pair[1], 2.0/1440)
+	}
+	y, m, d := e.Date()
+	assert.Equal(t, [3]int{2018, 1, 31}, [3]int{y, m, d})
+
+	// visible in Taipei, and the moon sets during the eclipse in New York
+	l, ok := Observe(zcal.Observer{Latitude: 25.033, Longitude: 121.5654, TZ: 8}, e)
+	assert.True(t, ok)
+	assert.Equal(t, e.JD, l.Max)
+	assert.Equal(t, e.PartialBegin, l.Begin)
+	assert.True(t, l.Altitude > 50)
+	l, ok = Observe(zcal.Observer{Latitude: 40.7128, Longitude: -74.0060, TZ: -5}, e)
+	assert.True(t, ok)
+	assert.InDelta(t, clock(2018, 1, 31, 8, 30), l.Max, 1.0/1440)
+	assert.True(t, l.Altitude < 0)
+	_, ok = Observe(zcal.Observer{Latitude: 51.4769, Longitude: 0, TZ: 0}, e)
+	assert.False(t, ok)
+}
+
+func TestObserveSolar(t *testing.T) {
+	e := Solar(zcal.GregorianCalendarToJD(2017, 8, 1), zcal.GregorianCalendarToJD(2017, 9, 1), 0)[0]
+
+	// totality in Nashville, CDT
+	l, ok := Observe(zcal.Observer{Latitude: 36.1627, Longitude: -86.7816, TZ: -5}, e)
+	assert.True(t, ok)
+	assert.InDelta(t, clock(2017, 8, 21, 11, 58), l.Begin, 2.0/1440)
+	assert.InDelta(t, clock(2017, 8, 21, 13, 28), l.Max, 2.0/1440)
+	assert.InDelta(t, clock(2017, 8, 21, 14, 54), l.End, 2.0/1440)
+	assert.True(t, l.Magnitude > 1)
+	assert.InDelta(t, 64, l.Altitude, 1)
+
+	// night in Taipei, and outside the penumbra in Sydney
+	_, ok = Observe(zcal.Observer{Latitude: 25.033, Longitude: 121.5654, TZ: 8}, e)
+	assert.False(t, ok)
+	_, ok = Observe(zcal.Observer{Latitude: -33.8688, Longitude: 151.2093, TZ: 10}, e)
+	assert.False(t, ok)
+
+	// the annular eclipse of 2020-06-21 is partial in Taipei
+	e = Solar(zcal.GregorianCalendarToJD(2020, 6, 1), zcal.GregorianCalendarToJD(2020, 7, 1), 8)[0]
+	assert.Equal(t, Annular, e.Type)
+	assert.InDelta(t, .994, e.Magnitude, .005)
+	l, ok = Observe(zcal.Observer{Latitude: 25.033, Longitude: 121.5654, TZ: 8}, e)
+	assert.True(t, ok)
+	assert.InDelta(t, clock(2020, 6, 21, 14, 50), l.Begin, 3.0/1440)
+	assert.InDelta(t, clock(2020, 6, 21, 16, 14), l.Max, 3.0/1440)
+	assert.InDelta(t, .95, l.Magnitude, .03)
+	assert.True(t, l.Begin < l.Max && l.Max < l.End)
+}
+
+func TestAncientEclipse(t *testing.T) {
+	// 春秋 桓公三年「秋七月壬辰朔，日有食之，既」, 709 BC July 17 (Julian)
+	from := zcal.JulianCalendarToJD(-708, 7, 1)
+	eclipses := Solar(from, from+31, 8)
+	assert.Len(t, eclipses, 1)
+	e := eclipses[0]
+	assert.Equal(t, Total, e.Type)
+	y, m, d := e.Date()
+	assert.Equal(t, [3]int{-709, 7, 17}, [3]int{y, m, d})
+	assert.Equal(t, zcal.NewGongheDate(133, 6, 6), e.GongheDate())
+	assert.Equal(t, "壬辰", zcal.JDToStemBranch(e.JD))
+
+	// total in 曲阜, the capital of 魯
+	l, ok := Observe(zcal.Observer{Latitude: 35.6, Longitude: 117.0, TZ: 8}, e)
+	assert.True(t, ok)
+	assert.True(t, l.Magnitude > 1)
+}
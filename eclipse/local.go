@@ -0,0 +1,135 @@
+package eclipse
+
+import (
+	"math"
+
+	"github.com/soniakeys/meeus/globe"
+	"github.com/soniakeys/meeus/parallax"
+	"github.com/soniakeys/unit"
+	"github.com/tzengyuxio/zcal"
+)
+
+// kmPerAU is the astronomical unit in kilometers.
+const kmPerAU = 149597870.7
+
+// Local is the circumstances of an eclipse at an observer, in the local time
+// of the observer.
+type Local struct {
+	// Begin and End are the first and the last contacts of a solar eclipse,
+	// or the partial phase of a lunar eclipse (the penumbral phase of a
+	// penumbral eclipse), and Max is the greatest eclipse.
+	Begin, Max, End float64
+	// Magnitude is the fraction of the diameter of the sun covered by the
+	// moon at Max, or Eclipse.Magnitude for a lunar eclipse.
+	Magnitude float64
+	// Altitude is the geometric altitude in degrees of the sun or the moon
+	// at Max, which may be negative if the eclipse is visible only for a part
+	// of the time.
+	Altitude float64
+}
+
+// Observe returns the circumstances of the eclipse at the observer. It
+// returns false if the eclipse is not visible at the location, i.e. the sun
+// or the moon is below the horizon during the whole eclipse, or the location
+// is outside the penumbra of the moon for a solar eclipse.
+func Observe(o zcal.Observer, e Eclipse) (Local, bool) {
+	if e.Kind == LunarEclipse {
+		return observeLunar(o, e)
+	}
+	return observeSolar(o, e)
+}
+
+func observeLunar(o zcal.Observer, e Eclipse) (Local, bool) {
+	shift := (o.TZ - e.tz) / 24
+	l := Local{Begin: e.PartialBegin + shift, Max: e.JD + shift, End: e.PartialEnd + shift, Magnitude: e.Magnitude}
+	if e.Type == Penumbral {
+		l.Begin, l.End = e.PenumbralBegin+shift, e.PenumbralEnd+shift
+	}
+	l.Altitude = o.MoonAltitude(l.Max)
+	return l, visible(l, func(jd float64) bool { return o.MoonAltitude(jd) > 0 })
+}
+
+// visible returns true if above is true at any time of the eclipse, sampled
+// every 5 minutes.
+func visible(l Local, above func(jd float64) bool) bool {
+	for jd := l.Begin; jd < l.End; jd += 5.0 / 1440 {
+		if above(jd) {
+			return true
+		}
+	}
+	return above(l.End)
+}
+
+// separation returns the angular distance between the topocentric centers
+// of the sun and the moon, and the sum of their semidiameters, in degrees at
+// the local Julian date jd.
+func separation(o zcal.Observer, jd float64) (d, sum float64) {
+	ut := float64(zcal.LocalJD(jd).UT(o.TZ))
+	jde := float64(zcal.JD(ut).TT())
+	ρs, ρc := globe.Earth76.ParallaxConstants(unit.AngleFromDeg(o.Latitude), o.Elevation)
+	L := unit.AngleFromDeg(-o.Longitude) // west is positive in Meeus
+
+	α0, δ0, R := zcal.ApparentSunEquatorial(nil, jde)
+	α0, δ0 = parallax.Topocentric(α0, δ0, R, ρs, ρc, L, ut)
+	α, δ, Δ := zcal.ApparentMoonEquatorial(jde)
+	α, δ = parallax.Topocentric(α, δ, Δ/kmPerAU, ρs, ρc, L, ut)
+
+	// the haversine formula is precise at small distances
+	sδ := math.Sin((δ - δ0).Rad() / 2)
+	sα := math.Sin((α - α0).Rad() / 2)
+	d = 2 * math.Asin(math.Sqrt(sδ*sδ+math.Cos(δ.Rad())*math.Cos(δ0.Rad())*sα*sα)) * 180 / math.Pi
+
+	sh := math.Sin(o.MoonAltitude(jd) * math.Pi / 180)
+	return d, sunSemidiameter(R) + topocentricMoonSemidiameter(Δ, sh)
+}
+
+func observeSolar(o zcal.Observer, e Eclipse) (Local, bool) {
+	// the overlap of the disks
+	overlap := func(jd float64) float64 {
+		d, sum := separation(o, jd)
+		return sum - d
+	}
+	// the greatest eclipse by golden section search within 5 hours
+	const span = 5.0 / 24
+	jd := e.JD + (o.TZ-e.tz)/24
+	lo, hi := jd-span, jd+span
+	g := (math.Sqrt(5) - 1) / 2
+	for hi-lo > 1e-6 {
+		a, b := hi-g*(hi-lo), lo+g*(hi-lo)
+		if overlap(a) > overlap(b) {
+			hi = b
+		} else {
+			lo = a
+		}
+	}
+	max := (lo + hi) / 2
+	m := overlap(max)
+	if m <= 0 {
+		return Local{}, false
+	}
+	_, _, R := zcal.ApparentSun(nil, float64(zcal.LocalJD(max).UT(o.TZ).TT()))
+	sd := sunSemidiameter(R)
+
+	l := Local{
+		Begin:     bisect(overlap, max-span, max),
+		Max:       max,
+		End:       bisect(overlap, max+span, max),
+		Magnitude: m / (2 * sd),
+		Altitude:  o.SunAltitude(nil, max),
+	}
+	// the disks overlap through the Earth on the night side
+	return l, visible(l, func(jd float64) bool { return o.SunAltitude(nil, jd) > zcal.SunriseAltitude })
+}
+
+// bisect returns the root of f between out and in, where f(out) < 0 <= f(in).
+func bisect(f func(float64) float64, out, in float64) float64 {
+	for math.Abs(in-out) > 1e-6 {
+		mid := (out + in) / 2
+		if f(mid) < 0 {
+			out = mid
+		} else {
+			in = mid
+		}
+	}
+	return (out + in) / 2
+}
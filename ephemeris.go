@@ -0,0 +1,161 @@
+package zcal
+
+import (
+	_ "embed" // for the truncated VSOP87 Earth series
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/soniakeys/meeus/base"
+	"github.com/soniakeys/meeus/coord"
+	"github.com/soniakeys/meeus/nutation"
+	pp "github.com/soniakeys/meeus/planetposition"
+	"github.com/soniakeys/unit"
+)
+
+// Ephemeris provides the heliocentric ecliptic position of the Earth, at
+// equinox and ecliptic of date.
+//
+// *planetposition.V87Planet loaded from the full VSOP87 files implements it.
+// A nil Ephemeris given to the functions of this package means the default
+// ephemeris, see SetEphemeris.
+type Ephemeris interface {
+	Position(jde float64) (L, B unit.Angle, R float64)
+}
+
+//go:embed data/vsop87d_earth.txt
+var vsop87dEarth string
+
+var defaultEphemeris = struct {
+	sync.Mutex
+	e Ephemeris
+}{}
+
+// DefaultEphemeris returns the default ephemeris, which is the truncated
+// VSOP87 Earth series embedded in the package (Meeus, Appendix III) unless
+// another one is set by SetEphemeris or LoadVSOP87.
+//
+// The truncated series is accurate to about one second of arc for the
+// present epoch, i.e. a few seconds of time for solar terms.
+func DefaultEphemeris() Ephemeris {
+	defaultEphemeris.Lock()
+	defer defaultEphemeris.Unlock()
+	if defaultEphemeris.e == nil {
+		defaultEphemeris.e = parseTruncatedVSOP87(vsop87dEarth)
+	}
+	return defaultEphemeris.e
+}
+
+// SetEphemeris sets the default ephemeris, the embedded truncated series is
+// restored if e is nil.
+func SetEphemeris(e Ephemeris) {
+	defaultEphemeris.Lock()
+	defaultEphemeris.e = e
+	defaultEphemeris.Unlock()
+}
+
+// LoadVSOP87 loads the full VSOP87 Earth series from file VSOP87B.ear in
+// directory path, and sets it as the default ephemeris.
+func LoadVSOP87(path string) error {
+	e, err := pp.LoadPlanetPath(pp.Earth, path)
+	if err != nil {
+		return err
+	}
+	SetEphemeris(e)
+	return nil
+}
+
+// ephemeris returns e, or the default ephemeris if e is nil.
+func ephemeris(e Ephemeris) Ephemeris {
+	if v, ok := e.(*pp.V87Planet); e == nil || (ok && v == nil) {
+		return DefaultEphemeris()
+	}
+	return e
+}
+
+// ApparentSun returns the apparent geocentric ecliptic position of the sun,
+// at equinox of date in the FK5 frame, including nutation and aberration.
+// See solar.ApparentVSOP87.
+func ApparentSun(e Ephemeris, jde float64) (λ, β unit.Angle, R float64) {
+	l, b, R := ephemeris(e).Position(jde)
+	s := l + math.Pi
+	// FK5 correction, (25.9) p. 166
+	λp := base.Horner(base.J2000Century(jde), s.Rad(), -1.397*math.Pi/180, -.00031*math.Pi/180)
+	sλp, cλp := math.Sincos(λp)
+	β = unit.AngleFromSec(.03916).Mul(cλp-sλp) - b
+	s -= unit.AngleFromSec(.09033)
+
+	Δψ, _ := nutation.Nutation(jde)
+	a := unit.AngleFromSec(-20.4898).Div(R) // aberration, (25.10) p. 167
+	return (s + Δψ + a).Mod1(), β, R
+}
+
+// ApparentSunEquatorial returns the apparent geocentric equatorial position
+// of the sun, see ApparentSun.
+func ApparentSunEquatorial(e Ephemeris, jde float64) (α unit.RA, δ unit.Angle, R float64) {
+	λ, β, R := ApparentSun(e, jde)
+	_, Δε := nutation.Nutation(jde)
+	ε := nutation.MeanObliquity(jde) + Δε
+	sε, cε := ε.Sincos()
+	α, δ = coord.EclToEq(λ, β, sε, cε)
+	return
+}
+
+type vsop87Term struct {
+	a, b, c float64
+}
+
+// truncatedVSOP87 is a VSOP87D series of the Earth, whose results are at
+// equinox and ecliptic of date.
+type truncatedVSOP87 struct {
+	l, b, r [6][]vsop87Term
+}
+
+// parseTruncatedVSOP87 parses lines of "<L|B|R><power> A B C", empty lines
+// and lines begin with # are ignored.
+func parseTruncatedVSOP87(data string) *truncatedVSOP87 {
+	v := &truncatedVSOP87{}
+	for _, line := range strings.Split(data, "\n") {
+		f := strings.Fields(line)
+		if len(f) != 4 || strings.HasPrefix(f[0], "#") {
+			continue
+		}
+		n := int(f[0][1] - '0')
+		var t vsop87Term
+		t.a, _ = strconv.ParseFloat(f[1], 64)
+		t.b, _ = strconv.ParseFloat(f[2], 64)
+		t.c, _ = strconv.ParseFloat(f[3], 64)
+		t.a *= 1e-8
+		switch f[0][0] {
+		case 'L':
+			v.l[n] = append(v.l[n], t)
+		case 'B':
+			v.b[n] = append(v.b[n], t)
+		case 'R':
+			v.r[n] = append(v.r[n], t)
+		}
+	}
+	return v
+}
+
+// Position returns the heliocentric ecliptic position of the Earth at
+// equinox and ecliptic of date.
+func (v *truncatedVSOP87) Position(jde float64) (L, B unit.Angle, R float64) {
+	τ := base.J2000Century(jde) * .1
+	sum := func(series [6][]vsop87Term) float64 {
+		var cf [6]float64
+		for x, terms := range series {
+			// sum terms in reverse order to preserve accuracy
+			for y := len(terms) - 1; y >= 0; y-- {
+				t := &terms[y]
+				cf[x] += t.a * math.Cos(t.b+t.c*τ)
+			}
+		}
+		return base.Horner(τ, cf[:]...)
+	}
+	L = unit.Angle(unit.PMod(sum(v.l), 2*math.Pi))
+	B = unit.Angle(sum(v.b))
+	R = sum(v.r)
+	return
+}
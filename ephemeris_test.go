@@ -0,0 +1,59 @@
+package zcal_test
+
+import (
+	"testing"
+
+	pp "github.com/soniakeys/meeus/planetposition"
+	"github.com/soniakeys/meeus/solstice"
+	"github.com/soniakeys/unit"
+	"github.com/stretchr/testify/assert"
+	. "github.com/tzengyuxio/zcal"
+)
+
+func TestApparentSun(t *testing.T) {
+	// Example 25.b, p. 169: 1992 October 13.0 TD
+	λ, β, R := ApparentSun(nil, 2448908.5)
+	assert.InDelta(t, unit.NewAngle(' ', 199, 54, 21.818).Deg(), λ.Deg(), 3.0/3600)
+	assert.InDelta(t, unit.AngleFromSec(.72).Deg(), β.Deg(), 1.0/3600)
+	assert.InDelta(t, .99760853, R, 1e-6)
+
+	α, δ, _ := ApparentSunEquatorial(nil, 2448908.5)
+	assert.InDelta(t, unit.NewRA(13, 13, 30.749).Deg(), unit.Angle(α).Deg(), 3.0/3600)
+	assert.InDelta(t, unit.NewAngle('-', 7, 47, 1.74).Deg(), δ.Deg(), 3.0/3600)
+}
+
+func TestDefaultEphemeris(t *testing.T) {
+	e := DefaultEphemeris()
+	assert.NotNil(t, e)
+	assert.Equal(t, e, DefaultEphemeris())
+
+	for _, y := range []int{1000, 1384, 1912, 2000, 2017, 2500} {
+		// solstice.December is accurate to about one minute in these years
+		assert.InDelta(t, solstice.December(y), float64(SolarTermTT(nil, y, DongZhi)), 1.0/1440, "For year %d", y)
+		assert.InDelta(t, solstice.June(y), float64(SolarTermTT(e, y, XiaZhi)), 1.0/1440, "For year %d", y)
+	}
+
+	// a nil *V87Planet is the same as nil
+	var v *pp.V87Planet
+	s1, m1 := CalcDongzhiAndShuo(v, 2017, 8)
+	s2, m2 := CalcDongzhiAndShuo(nil, 2017, 8)
+	assert.Equal(t, s2, s1)
+	assert.Equal(t, m2, m1)
+}
+
+func TestSetEphemeris(t *testing.T) {
+	defer SetEphemeris(nil)
+
+	assert.Error(t, LoadVSOP87("/nonexistent"))
+	e := DefaultEphemeris()
+	SetEphemeris(fixedEarth{})
+	assert.Equal(t, fixedEarth{}, DefaultEphemeris())
+	SetEphemeris(nil)
+	assert.Equal(t, e, DefaultEphemeris())
+}
+
+type fixedEarth struct{}
+
+func (fixedEarth) Position(jde float64) (L, B unit.Angle, R float64) {
+	return 0, 0, 1
+}
@@ -5,8 +5,6 @@ import (
 
 	"github.com/soniakeys/meeus/base"
 	mp "github.com/soniakeys/meeus/moonphase"
-	pp "github.com/soniakeys/meeus/planetposition"
-	"github.com/soniakeys/meeus/solstice"
 )
 
 var secondsOfDay float64 = 24 * 60 * 60
@@ -30,12 +28,12 @@ func JDToWeekday(jd float64) int {
 	return wd
 }
 
-func CalcDongzhiAndShuo(e *pp.V87Planet, year int, tz float64) (sJD, mJD float64) {
-	offset := tz / 24
-	sJD = solstice.December2(year, e)
-	jy := base.JDEToJulianYear(sJD)
-	mJD = mp.New(jy)
-	sJD += offset
-	mJD += offset
-	return
+// CalcDongzhiAndShuo returns the December solstice (冬至) of year and the new
+// moon (朔) nearest to it, both are the local time of tz hours offset, i.e.
+// ΔT is subtracted from the JDE and tz/24 is added. A nil e means the default
+// ephemeris.
+func CalcDongzhiAndShuo(e Ephemeris, year int, tz float64) (sJD, mJD float64) {
+	s := SolarTermTT(e, year, DongZhi)
+	m := TT(mp.New(base.JDEToJulianYear(float64(s))))
+	return float64(s.UT().Local(tz)), float64(m.UT().Local(tz))
 }
@@ -5,11 +5,7 @@ import (
 	"math"
 	"testing"
 
-	"github.com/soniakeys/meeus/solar"
-
 	"github.com/soniakeys/meeus/julian"
-	pp "github.com/soniakeys/meeus/planetposition"
-	"github.com/soniakeys/meeus/solstice"
 	sexa "github.com/soniakeys/sexagesimal"
 	"github.com/soniakeys/unit"
 	"github.com/stretchr/testify/assert"
@@ -86,8 +82,7 @@ func TestJDToStemBranch(t *testing.T) {
 }
 
 func NoTestFindEpoch(t *testing.T) {
-	e, err := pp.LoadPlanetPath(pp.Earth, "/Users/tzengyuxio/SDK/VI_81")
-	assert.Nil(t, err)
+	e := DefaultEphemeris()
 
 	for i := 20202; i > -20202; i-- {
 		sj, mj := CalcDongzhiAndShuo(e, i, 8)
@@ -115,8 +110,7 @@ func NoTestFindEpoch(t *testing.T) {
 }
 
 func NoTestFindTZEpoch(t *testing.T) {
-	e, err := pp.LoadPlanetPath(pp.Earth, "/Users/tzengyuxio/SDK/VI_81")
-	assert.Nil(t, err)
+	e := DefaultEphemeris()
 
 	tz := 8.0 //(110.0 / 15.0)
 	offset := tz / 24.0
@@ -186,36 +180,35 @@ func NoTestStemBranchAndGanzhi(t *testing.T) {
 }
 
 func TestFindGHZeroYear(t *testing.T) {
-	e, err := pp.LoadPlanetPath(pp.Earth, "/Users/tzengyuxio/SDK/VI_81")
-	assert.Nil(t, err)
+	e := DefaultEphemeris()
 
 	jd := julian.CalendarJulianToJD(-842, 2, 12.260417)
 	fmt.Printf("zero gonghe %f\n", jd)
 
 	var jdw, jds float64
 	for i := -842; i <= -840; i++ {
-		jdw = solstice.December2(i-1, e)
-		jds = solstice.March2(i, e)
+		jdw = SolarTermJD(e, i-1, DongZhi, 0)
+		jds = SolarTermJD(e, i, ChunFen, 0)
 		fmt.Printf("GH Year(%d): %f (%0.2f)\n", i, (jdw+jds)/2, jds-jdw)
 		// p, b0, l0 := solardisk.Ephemeris(jds, e)
 		// fmt.Printf("P, B0, L0: %.2f, %+2.f, %.2f\n", p, b0, l0)
-		alpha, delta, _ := solar.ApparentEquatorialVSOP87(e, jds)
+		alpha, delta, _ := ApparentSunEquatorial(e, jds)
 		fmt.Printf("α: %.3d  δ: %+.2d\n", sexa.FmtRA(alpha), sexa.FmtAngle(delta))
 	}
 
 	fmt.Println("--------------------")
 	fmt.Printf("GH Year(%d): %f\n", -842, jd)
-	alpha, delta, _ := solar.ApparentEquatorialVSOP87(e, jd)
+	alpha, delta, _ := ApparentSunEquatorial(e, jd)
 	fmt.Printf("JD: %08.6f, α: %.3d  δ: %+.2d\n", jd, sexa.FmtRA(alpha), sexa.FmtAngle(delta))
 	jdplus := jd - 2.866585
-	alpha, delta, _ = solar.ApparentEquatorialVSOP87(e, jdplus)
+	alpha, delta, _ = ApparentSunEquatorial(e, jdplus)
 	fmt.Printf("JD: %08.6f, α: %.3d  δ: %+.2d\n", jdplus, sexa.FmtRA(alpha), sexa.FmtAngle(delta))
 	// fmt.Printf("JD: %08.6f, α: %.3d  δ: %+.2d\n", jdplus, sexa.FmtAngle(unit.RAFromRad(alpha)), sexa.FmtAngle(unit.AngleFromDeg(delta)))
 	fmt.Println("--------------------")
-	lambda, beta, _ := solar.ApparentVSOP87(e, jd)
+	lambda, beta, _ := ApparentSun(e, jd)
 	fmt.Printf("JD: %08.6f, λ: %.3d  β: %+.2d\n", jd, sexa.FmtAngle(lambda), sexa.FmtAngle(beta)) // 查書的春分點
 	jdplus2 := jd - 0.304905
-	lambda, beta, _ = solar.ApparentVSOP87(e, jdplus2)
+	lambda, beta, _ = ApparentSun(e, jdplus2)
 	fmt.Printf("JD: %08.6f, λ: %.3d  β: %+.2d\n", jdplus2, sexa.FmtAngle(lambda), sexa.FmtAngle(beta)) // 計算出來的春分點
 	fmt.Println("--------------------")
 
@@ -0,0 +1,234 @@
+// Package era converts the dates of the reign eras (年號) of Chinese history,
+// e.g. 貞觀三年正月初一, to Julian dates and back.
+//
+// The embedded table begins at 共和元年 (841 BC), the lunar year of
+// zcal.JDOfGongheFirstDay and the first year of the reliable chronology of
+// China, and ends at 宣統. The years before the era names, which began at 建元
+// of 漢武帝, are counted by the reigns of the rulers, e.g. 魯桓公三年. The
+// concurrent states are listed as well, e.g. 魯 of 春秋, 三國, 南北朝, 遼 and 金,
+// so that a day may be in several eras.
+//
+// The lunar dates follow the modern rules of zcal.JDToChineseLunar, which may
+// differ from the historical calendars by a day, or a month around the leap
+// months. The years of the calendars beginning at other months, e.g. 周正
+// and 顓頊曆 before 104 BC, are counted from 正月 as well.
+package era
+
+import (
+	_ "embed" // for the table of eras
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tzengyuxio/zcal"
+)
+
+//go:embed data/eras.txt
+var erasData string
+
+// eras are the eras of the table ordered by Start.
+var eras = parseEras(erasData)
+
+// Errors returned by Parse and Date.JD.
+var (
+	ErrUnknownEra = errors.New("era: unknown era")
+	ErrOutOfEra   = errors.New("era: date out of the era")
+	ErrSyntax     = errors.New("era: invalid syntax")
+)
+
+// Era is a reign era of a dynasty, or the reign of a ruler whose years have
+// no era name.
+type Era struct {
+	Dynasty string // the dynasty or the state, e.g. 唐, 魯 and 蜀漢
+	Ruler   string // e.g. 太宗 and 桓公
+	Name    string // e.g. 貞觀, empty for the years of the reign of Ruler
+	// Start is the first day of the era, and End is the first day after the
+	// era, which are the first days of lunar months except a few, e.g. the
+	// end of 宣統 at 十二月廿六.
+	Start, End zcal.LunarDate
+	// First is the lunar year of 元年, which is Start.Year unless the era
+	// continues the years of an earlier era, e.g. 洪武 of 明成祖.
+	First int
+}
+
+// String returns the era with the dynasty, e.g. 唐貞觀 and 魯桓公.
+func (e Era) String() string {
+	if e.Name == "" {
+		return e.Dynasty + e.Ruler
+	}
+	return e.Dynasty + e.Name
+}
+
+// Years returns the number of the years of the era, counting the first and
+// the last years which may be partial.
+func (e Era) Years() int {
+	last := e.End.Year
+	if e.End.Month == 1 && !e.End.IsLeap && e.End.Day == 1 {
+		last--
+	}
+	return last - e.First + 1
+}
+
+// contains returns true if the lunar date l is in the era.
+func (e Era) contains(l zcal.LunarDate) bool {
+	return order(e.Start) <= order(l) && order(l) < order(e.End)
+}
+
+// labels returns the names of the era accepted by Find and Parse.
+func (e Era) labels() []string {
+	if e.Name == "" {
+		return []string{e.Ruler, e.Dynasty + e.Ruler}
+	}
+	return []string{e.Name, e.Dynasty + e.Name, e.Dynasty + e.Ruler + e.Name}
+}
+
+// order returns an integer in the order of lunar dates, a leap month is after
+// the month of the same number.
+func order(l zcal.LunarDate) int {
+	n := l.Year*10000 + l.Month*100 + l.Day
+	if l.IsLeap {
+		n += 50
+	}
+	return n
+}
+
+// Eras returns all the eras ordered by the start.
+func Eras() []Era {
+	return append([]Era(nil), eras...)
+}
+
+// Find returns the eras of the name, which is the era name with or without
+// the dynasty and the ruler, e.g. 貞觀, 唐貞觀 and 唐太宗貞觀, or the ruler with
+// or without the dynasty for the years without era names, e.g. 魯桓公. An era
+// name may be used by several dynasties, e.g. 建武.
+func Find(name string) []Era {
+	var found []Era
+	for _, e := range eras {
+		for _, l := range e.labels() {
+			if l == name {
+				found = append(found, e)
+				break
+			}
+		}
+	}
+	return found
+}
+
+// Date is a date of an era.
+type Date struct {
+	Era    Era
+	Year   int // the year of the era, 1 for 元年
+	Month  int
+	IsLeap bool
+	Day    int
+}
+
+// FromJD returns the dates of the eras of the Julian date, more than one for
+// the concurrent states, or nil out of the eras, e.g. before 共和元年. A nil e
+// means the default ephemeris.
+func FromJD(e zcal.Ephemeris, jd float64) []Date {
+	y, m, leap, d, err := zcal.JDToChineseLunarChecked(e, jd)
+	if err != nil {
+		return nil
+	}
+	l := zcal.LunarDate{Year: y, Month: m, IsLeap: leap, Day: d}
+	var dates []Date
+	for _, era := range eras {
+		if era.contains(l) {
+			dates = append(dates, Date{era, l.Year - era.First + 1, l.Month, l.IsLeap, l.Day})
+		}
+	}
+	return dates
+}
+
+// LunarDate returns the date in Chinese lunar calendar of zcal.
+func (d Date) LunarDate() zcal.LunarDate {
+	return zcal.LunarDate{Year: d.Era.First + d.Year - 1, Month: d.Month, IsLeap: d.IsLeap, Day: d.Day}
+}
+
+// JD returns the Julian date of the date. ErrOutOfEra is returned if the
+// date is not in the era, and a *zcal.DateError if the month or the day does
+// not exist. A nil e means the default ephemeris.
+func (d Date) JD(e zcal.Ephemeris) (float64, error) {
+	l := d.LunarDate()
+	if !d.Era.contains(l) {
+		return 0, ErrOutOfEra
+	}
+	return zcal.ChineseLunarToJD(e, l.Year, l.Month, l.IsLeap, l.Day)
+}
+
+// String returns the date in Chinese, e.g. 唐貞觀三年正月初一.
+func (d Date) String() string {
+	return d.Era.String() + yearName(d.Year) + "年" + zcal.LunarMonthName(d.Month, d.IsLeap) + zcal.LunarDayName(d.Day)
+}
+
+// parseEras parses the table of eras, see data/eras.txt for the format.
+func parseEras(data string) []Era {
+	var list []Era
+	for i, line := range strings.Split(data, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 4 {
+			panic(fmt.Sprintf("era: line %d: too few fields", i+1))
+		}
+		for len(fields) < 6 {
+			fields = append(fields, "")
+		}
+		e := Era{Dynasty: fields[0], Ruler: fields[1], Name: fields[2], Start: parseLunarDate(i, fields[3])}
+		e.First = e.Start.Year
+		if fields[4] != "" {
+			e.End = parseLunarDate(i, fields[4])
+		}
+		if fields[5] != "" {
+			first, err := strconv.Atoi(fields[5])
+			if err != nil {
+				panic(fmt.Sprintf("era: line %d: %v", i+1, err))
+			}
+			e.First = first
+		}
+		list = append(list, e)
+	}
+
+	// an era without end ends at the next era of the dynasty
+	for i := range list {
+		for j := i + 1; j < len(list) && list[i].End.Month == 0; j++ {
+			if list[j].Dynasty == list[i].Dynasty {
+				list[i].End = list[j].Start
+			}
+		}
+		if list[i].End.Month == 0 {
+			panic("era: no end of " + list[i].String())
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return order(list[i].Start) < order(list[j].Start)
+	})
+	return list
+}
+
+// parseLunarDate parses the first day of a lunar month of the table, e.g.
+// 627-1 and 760-閏4, or a day, e.g. 1911-12-26.
+func parseLunarDate(line int, s string) zcal.LunarDate {
+	// the year may be negative
+	f := strings.Split(strings.TrimPrefix(s, "-"), "-")
+	if len(f) == 2 || len(f) == 3 {
+		m := strings.TrimPrefix(f[1], "閏")
+		year, err := strconv.Atoi(f[0])
+		if strings.HasPrefix(s, "-") {
+			year = -year
+		}
+		month, err2 := strconv.Atoi(m)
+		day, err3 := 1, error(nil)
+		if len(f) == 3 {
+			day, err3 = strconv.Atoi(f[2])
+		}
+		if err == nil && err2 == nil && err3 == nil && month >= 1 && month <= 12 && day >= 1 && day <= 30 {
+			return zcal.LunarDate{Year: year, Month: month, IsLeap: m != f[1], Day: day}
+		}
+	}
+	panic(fmt.Sprintf("era: line %d: invalid date %q", line+1, s))
+}
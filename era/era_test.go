@@ -0,0 +1,191 @@
+package era_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tzengyuxio/zcal"
+	. "github.com/tzengyuxio/zcal/era"
+)
+
+// names returns the dates in Chinese.
+func names(dates []Date) []string {
+	var s []string
+	for _, d := range dates {
+		s = append(s, d.String())
+	}
+	return s
+}
+
+func TestEras(t *testing.T) {
+	eras := Eras()
+	// the table begins at the lunar year of 共和元年
+	assert.Equal(t, "周共和", eras[0].String())
+	assert.Equal(t, zcal.LunarDateFromJD(nil, zcal.JDOfGongheFirstDay).Year, eras[0].Start.Year)
+	assert.Equal(t, "清宣統", eras[len(eras)-1].String())
+	for i, e := range eras {
+		assert.True(t, e.Start.Year*100+e.Start.Month < e.End.Year*100+e.End.Month, "For %v", e)
+		assert.True(t, e.Years() > 0, "For %v", e)
+		if i > 0 {
+			assert.False(t, e.Start.Year < eras[i-1].Start.Year, "For %v", e)
+		}
+	}
+
+	for _, pair := range []struct {
+		name  string
+		eras  []string
+		years int
+	}{
+		{"貞觀", []string{"唐貞觀"}, 23},
+		{"唐太宗貞觀", []string{"唐貞觀"}, 23},
+		{"康熙", []string{"清康熙"}, 61},
+		{"共和", []string{"周共和"}, 14},
+		{"魯桓公", []string{"魯桓公"}, 18},
+		{"桓公", []string{"魯桓公"}, 18},
+		{"始皇帝", []string{"秦始皇帝"}, 37},
+		{"建武", []string{"東漢建武", "西晉建武", "東晉建武", "齊建武"}, 32},
+		{"太和", []string{"魏太和", "東晉太和", "北魏太和"}, 7},
+		{"天福", []string{"後晉天福", "後漢天福"}, 9},
+	} {
+		found := Find(pair.name)
+		var s []string
+		for _, e := range found {
+			s = append(s, e.String())
+		}
+		assert.Equal(t, pair.eras, s, "For %s", pair.name)
+		assert.Equal(t, pair.years, found[0].Years(), "For %s", pair.name)
+	}
+	assert.Empty(t, Find("唐建武"))
+
+	// 後漢 continues the years of 後晉
+	e := Find("後漢天福")[0]
+	assert.Equal(t, 936, e.First)
+	assert.Equal(t, 947, e.Start.Year)
+}
+
+func TestFromJD(t *testing.T) {
+	for _, pair := range []struct {
+		jd    float64
+		dates []string
+	}{
+		// 武昌起義
+		{zcal.GregorianCalendarToJD(1911, 10, 10), []string{"清宣統三年八月十九"}},
+		{zcal.JulianCalendarToJD(627, 1, 23), []string{"唐貞觀元年正月初一"}},
+		{zcal.JulianCalendarToJD(627, 1, 22), []string{"唐武德九年十二月三十"}},
+		// 三國
+		{zcal.JulianCalendarToJD(230, 6, 1), []string{"蜀漢建興八年五月初四", "魏太和四年五月初四", "吳黃龍二年五月初四"}},
+		{zcal.GregorianCalendarToJD(1640, 1, 1), []string{"明崇禎十二年十二月初九", "清崇德四年十二月初九"}},
+		// the eclipse of 春秋 is recorded in 七月 of 周正
+		{zcal.JulianCalendarToJD(-708, 7, 17), []string{"周桓王十一年六月初一", "魯桓公三年六月初一"}},
+		{zcal.JDOfGongheFirstDay, []string{"周共和元年正月初二"}},
+		{zcal.JDOfGongheFirstDay - 1, []string{"周共和元年正月初一"}},
+		{zcal.JDOfGongheFirstDay - 2, nil},
+		// 清帝退位
+		{zcal.GregorianCalendarToJD(1912, 2, 12), []string{"清宣統三年十二月廿五"}},
+		{zcal.GregorianCalendarToJD(1912, 2, 13), nil},
+		{zcal.GregorianCalendarToJD(1912, 2, 18), nil},
+		// out of the supported years
+		{1e8, nil},
+		{-1e8, nil},
+	} {
+		assert.Equal(t, pair.dates, names(FromJD(nil, pair.jd)), "For %v", pair.jd)
+	}
+
+	// round trip at the first days of the eras
+	for _, e := range Eras() {
+		d := Date{Era: e, Year: e.Start.Year - e.First + 1, Month: e.Start.Month, IsLeap: e.Start.IsLeap, Day: 1}
+		jd, err := d.JD(nil)
+		if err != nil {
+			// the leap month is not the one of the modern rules
+			assert.True(t, e.Start.IsLeap, "For %v", e)
+			continue
+		}
+		assert.Contains(t, FromJD(nil, jd), d)
+		assert.NotContains(t, FromJD(nil, jd-1), d)
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, pair := range []struct {
+		s     string
+		dates []string
+	}{
+		{"貞觀三年", []string{"唐貞觀三年正月初一"}},
+		{"唐貞觀三年正月初一", []string{"唐貞觀三年正月初一"}},
+		{" 唐太宗貞觀3年1月1日 ", []string{"唐貞觀三年正月初一"}},
+		{"康熙六十一年十一月十三", []string{"清康熙六十一年十一月十三"}},
+		{"宣統三年八月十九日", []string{"清宣統三年八月十九"}},
+		{"開元元年", []string{"唐開元元年十二月初一"}},
+		{"開元二年臘月廿三", []string{"唐開元二年十二月廿三"}},
+		{"開元二年臘月卅", []string{"唐開元二年十二月三十"}},
+		{"魯桓公三年七月", []string{"魯桓公三年七月初一"}},
+		{"宣王十年", []string{"周宣王十年正月初一"}},
+		{"建武三年", []string{"東漢建武三年正月初一", "齊建武三年正月初一"}},
+		{"建武中元二年", []string{"東漢建武中元二年正月初一"}},
+		{"洪武三十五年", []string{"明洪武三十五年七月初一"}},
+		{"中平六年", []string{"東漢中平六年正月初一", "東漢中平六年十二月初一"}},
+		{"乾隆二十一年閏九月", []string{"清乾隆二十一年閏九月初一"}},
+		{"宣統三年十二月廿五", []string{"清宣統三年十二月廿五"}},
+	} {
+		dates, err := Parse(pair.s)
+		assert.NoError(t, err)
+		assert.Equal(t, pair.dates, names(dates), "For %s", pair.s)
+	}
+
+	for _, pair := range []struct {
+		s   string
+		err error
+	}{
+		{"foo", ErrUnknownEra},
+		{"貞觀二十四年", ErrOutOfEra},
+		{"洪武三十二年", ErrOutOfEra},
+		{"貞觀三", ErrSyntax},
+		{"貞觀三年十三月", ErrSyntax},
+		{"貞觀三年正月卅一", ErrSyntax},
+		{"貞觀十十年", ErrSyntax},
+		{"貞觀三年閏正月", ErrSyntax},
+		{"開元二年冬月卅", ErrSyntax},
+		// 閏九月 of the historical calendar is in 乾隆二十一年 by the
+		// modern rules
+		{"乾隆二十年閏九月", ErrSyntax},
+		{"宣統三年十二月廿六", ErrOutOfEra},
+	} {
+		_, err := Parse(pair.s)
+		assert.Equal(t, pair.err, err, "For %s", pair.s)
+	}
+}
+
+func TestDateJD(t *testing.T) {
+	for _, pair := range []struct {
+		s    string
+		date [3]int
+	}{
+		{"貞觀元年", [3]int{627, 1, 23}},
+		{"宣統三年八月十九", [3]int{1911, 10, 10}},
+		{"共和元年", [3]int{-841, 2, 11}},
+	} {
+		dates, err := Parse(pair.s)
+		assert.NoError(t, err)
+		jd, err := dates[0].JD(nil)
+		assert.NoError(t, err)
+		y, m, d := zcal.DefaultWesternCalendar.FromJD(jd)
+		assert.Equal(t, pair.date, [3]int{y, m, d}, "For %s", pair.s)
+	}
+
+	// 貞觀 ends in 649
+	_, err := Date{Era: Find("貞觀")[0], Year: 24, Month: 1, Day: 1}.JD(nil)
+	assert.Equal(t, ErrOutOfEra, err)
+	// 先天二年 ends before 十二月
+	_, err = Date{Era: Find("先天")[0], Year: 2, Month: 12, Day: 1}.JD(nil)
+	assert.Equal(t, ErrOutOfEra, err)
+	// the 30th day of a short month
+	for m := 1; m <= 12; m++ {
+		if zcal.ChineseLunarMonthDays(nil, 629, m, false) == 29 {
+			_, err = Date{Era: Find("貞觀")[0], Year: 3, Month: m, Day: 30}.JD(nil)
+			assert.True(t, errors.Is(err, zcal.ErrInvalidDay), fmt.Sprint(err))
+			break
+		}
+	}
+}
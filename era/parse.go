@@ -0,0 +1,167 @@
+package era
+
+import (
+	"strconv"
+	"strings"
+)
+
+var digits = []rune("〇一二三四五六七八九")
+
+// Parse parses a date of an era in Chinese, e.g. 貞觀三年, 唐貞觀三年正月初一 and
+// 魯桓公三年七月, see Find for the names of the eras. The month is 正月, or the
+// first month of the era in 元年, and the day is 初一 if they are omitted. The
+// numbers may be Arabic numerals as well, e.g. 貞觀3年1月1日.
+//
+// All the dates of the eras of the name are returned, e.g. 建武三年 of 東漢 and
+// 齊, but not of 西晉 and 東晉 whose 建武 ended in the first and second years.
+// ErrUnknownEra is returned if there is no such era, ErrOutOfEra if the date
+// is not in the eras of the name, and ErrSyntax if s is not a date, including
+// the months and the days which do not exist in the year, e.g. 閏正月 of a
+// year without it, by the default ephemeris.
+func Parse(s string) ([]Date, error) {
+	s = strings.TrimSpace(s)
+	var dates []Date
+	err := ErrUnknownEra
+	for _, e := range eras {
+		for _, l := range e.labels() {
+			if !strings.HasPrefix(s, l) {
+				continue
+			}
+			d, ok := parseDate(e, s[len(l):])
+			switch {
+			case !ok:
+				if err == ErrUnknownEra {
+					err = ErrSyntax
+				}
+			case !e.contains(d.LunarDate()):
+				err = ErrOutOfEra
+			case !exists(d):
+				if err != ErrOutOfEra {
+					err = ErrSyntax
+				}
+			default:
+				dates = append(dates, d)
+			}
+			break
+		}
+	}
+	if dates == nil {
+		return nil, err
+	}
+	return dates, nil
+}
+
+// parseDate parses the year, the month and the day of era e.
+func parseDate(e Era, s string) (Date, bool) {
+	d := Date{Era: e, Day: 1}
+	var ok bool
+	i := strings.Index(s, "年")
+	if i < 0 {
+		return d, false
+	}
+	if s[:i] == "元" {
+		d.Year, ok = 1, true
+	} else {
+		d.Year, ok = parseNumber(s[:i])
+	}
+	if !ok {
+		return d, false
+	}
+	s = s[i+len("年"):]
+
+	if s == "" {
+		// the first month of the year in the era
+		d.Month = 1
+		if d.LunarDate().Year == e.Start.Year {
+			d.Month, d.IsLeap = e.Start.Month, e.Start.IsLeap
+		}
+		return d, true
+	}
+	if i = strings.Index(s, "月"); i < 0 {
+		return d, false
+	}
+	m := s[:i]
+	if strings.HasPrefix(m, "閏") {
+		d.IsLeap, m = true, m[len("閏"):]
+	}
+	switch m {
+	case "正":
+		d.Month, ok = 1, true
+	case "冬":
+		d.Month, ok = 11, true
+	case "臘":
+		d.Month, ok = 12, true
+	default:
+		d.Month, ok = parseNumber(m)
+	}
+	if !ok || d.Month > 12 {
+		return d, false
+	}
+
+	s = strings.TrimSuffix(s[i+len("月"):], "日")
+	if s == "" {
+		return d, true
+	}
+	d.Day, ok = parseNumber(strings.TrimPrefix(s, "初"))
+	return d, ok && d.Day <= 30
+}
+
+// exists returns true if the month and the day of d exist in the lunar year.
+func exists(d Date) bool {
+	_, err := d.JD(nil)
+	return err == nil
+}
+
+// parseNumber parses a positive number less than 100 in Chinese, e.g. 三,
+// 十五, 廿三 and 六十一, or in Arabic numerals.
+func parseNumber(s string) (int, bool) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, n > 0
+	}
+	r := []rune(s)
+	tens := 0
+	switch {
+	case len(r) > 0 && r[0] == '廿':
+		tens, r = 2, r[1:]
+	case len(r) > 0 && r[0] == '卅':
+		tens, r = 3, r[1:]
+	case len(r) > 0 && r[0] == '十':
+		tens, r = 1, r[1:]
+	case len(r) > 1 && r[1] == '十':
+		if tens = indexDigit(r[0]); tens < 1 {
+			return 0, false
+		}
+		r = r[2:]
+	}
+	switch len(r) {
+	case 0:
+		return tens * 10, tens > 0
+	case 1:
+		d := indexDigit(r[0])
+		return tens*10 + d, d > 0
+	}
+	return 0, false
+}
+
+// indexDigit returns the value of Chinese digit r, or -1.
+func indexDigit(r rune) int {
+	for i, d := range digits {
+		if d == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// yearName returns the year of an era in Chinese, e.g. 元, 三 and 二十一.
+func yearName(n int) string {
+	switch {
+	case n == 1:
+		return "元"
+	case n < 10:
+		return string(digits[n])
+	case n%10 == 0:
+		return strings.TrimPrefix(string(digits[n/10])+"十", "一")
+	}
+	return yearName(n-n%10) + string(digits[n%10])
+}
@@ -0,0 +1,402 @@
+package zcal
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layouts for GongheDate.Format and GHCDate.Format, and the parse functions.
+//
+// A layout is a string of the following tokens, the other characters are
+// copied as they are, and a backslash escapes the next character.
+//
+//	YYYY  year, zero padded to 4 digits   2858
+//	Y     year                            2858
+//	MM    month, zero padded              02
+//	M     month                           2
+//	DD    day, zero padded                03
+//	D     day                             3
+//	NNN   day of year, zero padded        034
+//	N     day of year                     34
+//	CY    year in Chinese numerals        二八五八
+//	CM    month in Chinese numerals       二, 十二
+//	CD    day in Chinese numerals         三, 二十一
+//	GZ    stem-branch of day              甲子
+//	WW    weekday in Chinese              星期五
+//	W     weekday in English              Friday
+//	EE    era in Chinese                  共和
+//	E     era suffix                      GH for Gonghe, GHC for GHC
+//
+// The stem-branch and weekday are checked for syntax but otherwise ignored by
+// parsing, like time.Parse. YYYY parses exactly 4 digits after an optional
+// sign so that the compact layouts, e.g. YYYYMMDD, could be parsed, and Y
+// parses any number of digits.
+const (
+	LayoutISO     = "YYYY-MM-DD"
+	LayoutEra     = "Y-MM-DD E"
+	LayoutChinese = "EECY年CM月CD日"
+)
+
+// ErrLayout means the value does not match the layout.
+var ErrLayout = errors.New("value does not match layout")
+
+// ParseError describes a failure of parsing a date string.
+type ParseError struct {
+	Layout, Value string
+	Err           error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("zcal: parsing %q as %q: %v", e.Value, e.Layout, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// tokens are ordered by length so the longest one matches first.
+var layoutTokens = []string{
+	"YYYY", "NNN",
+	"MM", "DD", "CY", "CM", "CD", "GZ", "WW", "EE",
+	"Y", "M", "D", "N", "W", "E",
+}
+
+// nextToken splits layout into the literal prefix, the first token and the
+// remaining. The token is empty if there is none.
+func nextToken(layout string) (prefix, token, suffix string) {
+	var b strings.Builder
+	for i := 0; i < len(layout); i++ {
+		if layout[i] == '\\' && i+1 < len(layout) {
+			i++
+			b.WriteByte(layout[i])
+			continue
+		}
+		for _, t := range layoutTokens {
+			if strings.HasPrefix(layout[i:], t) {
+				return b.String(), t, layout[i+len(t):]
+			}
+		}
+		b.WriteByte(layout[i])
+	}
+	return b.String(), "", ""
+}
+
+var chineseWeekdays = []string{"日", "一", "二", "三", "四", "五", "六"}
+
+// chineseNumber returns n in [1, 99] in Chinese numerals, e.g. 二十一.
+func chineseNumber(n int) string {
+	switch {
+	case n <= 10:
+		return chineseDigits[n]
+	case n < 20:
+		return "十" + chineseDigits[n%10]
+	case n%10 == 0:
+		return chineseDigits[n/10] + "十"
+	}
+	return chineseDigits[n/10] + "十" + chineseDigits[n%10]
+}
+
+// chineseDigitString returns n digit by digit in Chinese numerals, e.g. 二八五八.
+func chineseDigitString(n int) string {
+	var b strings.Builder
+	if n < 0 {
+		b.WriteByte('-')
+		n = -n
+	}
+	for _, c := range strconv.Itoa(n) {
+		b.WriteString(chineseDigits[c-'0'])
+	}
+	return b.String()
+}
+
+// gongheFields is the content of a date shared by the Gonghe calendars.
+type gongheFields struct {
+	year, month, day int
+	jd               float64
+	ghc              bool
+}
+
+func (f gongheFields) era() string {
+	if f.ghc {
+		return "GHC"
+	}
+	return "GH"
+}
+
+func formatGonghe(f gongheFields, layout string) string {
+	var b strings.Builder
+	for layout != "" {
+		prefix, token, suffix := nextToken(layout)
+		b.WriteString(prefix)
+		switch token {
+		case "YYYY":
+			if f.year < 0 {
+				fmt.Fprintf(&b, "-%04d", -f.year)
+			} else {
+				fmt.Fprintf(&b, "%04d", f.year)
+			}
+		case "Y":
+			b.WriteString(strconv.Itoa(f.year))
+		case "MM":
+			fmt.Fprintf(&b, "%02d", f.month)
+		case "M":
+			b.WriteString(strconv.Itoa(f.month))
+		case "DD":
+			fmt.Fprintf(&b, "%02d", f.day)
+		case "D":
+			b.WriteString(strconv.Itoa(f.day))
+		case "NNN":
+			fmt.Fprintf(&b, "%03d", dayOfGongheYear(f.month, f.day))
+		case "N":
+			b.WriteString(strconv.Itoa(dayOfGongheYear(f.month, f.day)))
+		case "CY":
+			b.WriteString(chineseDigitString(f.year))
+		case "CM":
+			b.WriteString(chineseNumber(f.month))
+		case "CD":
+			b.WriteString(chineseNumber(f.day))
+		case "GZ":
+			b.WriteString(JDToGanzhi(f.jd))
+		case "WW":
+			b.WriteString("星期" + chineseWeekdays[JDToWeekday(f.jd)])
+		case "W":
+			b.WriteString(time.Weekday(JDToWeekday(f.jd)).String())
+		case "EE":
+			b.WriteString("共和")
+		case "E":
+			b.WriteString(f.era())
+		}
+		layout = suffix
+	}
+	return b.String()
+}
+
+// parseGonghe parses value by layout, in GHC if ghc is true.
+func parseGonghe(layout, value string, ghc bool) (f gongheFields, err error) {
+	f.ghc = ghc
+	f.month, f.day = 1, 1
+	doy := -1
+	hasYear, hasMonthDay := false, false
+	fail := func(err error) (gongheFields, error) {
+		return gongheFields{}, &ParseError{layout, value, err}
+	}
+	rest := value
+	for l := layout; l != ""; {
+		prefix, token, suffix := nextToken(l)
+		l = suffix
+		if !strings.HasPrefix(rest, prefix) {
+			return fail(ErrLayout)
+		}
+		rest = rest[len(prefix):]
+		var ok bool
+		switch token {
+		case "":
+			continue
+		case "YYYY":
+			f.year, rest, ok = parseInt(rest, 4, 4, true)
+			hasYear = true
+		case "Y":
+			f.year, rest, ok = parseInt(rest, 1, 0, true)
+			hasYear = true
+		case "MM":
+			f.month, rest, ok = parseInt(rest, 2, 2, false)
+			hasMonthDay = true
+		case "M":
+			f.month, rest, ok = parseInt(rest, 1, 2, false)
+			hasMonthDay = true
+		case "DD":
+			f.day, rest, ok = parseInt(rest, 2, 2, false)
+			hasMonthDay = true
+		case "D":
+			f.day, rest, ok = parseInt(rest, 1, 2, false)
+			hasMonthDay = true
+		case "NNN":
+			doy, rest, ok = parseInt(rest, 3, 3, false)
+		case "N":
+			doy, rest, ok = parseInt(rest, 1, 3, false)
+		case "CY":
+			f.year, rest, ok = parseChineseDigits(rest)
+			hasYear = true
+		case "CM":
+			f.month, rest, ok = parseChineseNumber(rest)
+			hasMonthDay = true
+		case "CD":
+			f.day, rest, ok = parseChineseNumber(rest)
+			hasMonthDay = true
+		case "GZ":
+			rest, ok = skipOneOf(rest, 60, StemBranch)
+		case "WW":
+			if ok = strings.HasPrefix(rest, "星期"); ok {
+				rest, ok = skipOneOf(rest[len("星期"):], 7, func(i int) string { return chineseWeekdays[i] })
+			}
+		case "W":
+			rest, ok = skipOneOf(rest, 7, func(i int) string { return time.Weekday(i).String() })
+		case "EE":
+			rest, ok = cutPrefix(rest, "共和")
+		case "E":
+			// GH is a prefix of GHC
+			rest, ok = cutPrefix(rest, f.era())
+			ok = ok && !strings.HasPrefix(rest, "C")
+		}
+		if !ok {
+			return fail(ErrLayout)
+		}
+	}
+	if rest != "" || !hasYear {
+		return fail(ErrLayout)
+	}
+
+	leap := LeapYearGonghe(f.year)
+	calendar := "Gonghe"
+	if ghc {
+		leap = LeapYearGHC(f.year + 1)
+		calendar = "GHC"
+	}
+	if doy >= 0 {
+		days := 365
+		if leap {
+			days = 366
+		}
+		if doy < 1 || doy > days {
+			return fail(&DateError{calendar, f.year, 0, doy, ErrInvalidDay})
+		}
+		m, d := gongheMonthDayOfYear(doy)
+		if hasMonthDay && (m != f.month || d != f.day) {
+			return fail(ErrLayout)
+		}
+		f.month, f.day = m, d
+	}
+	if err := validateGongheMonthDay(calendar, f.year, f.month, f.day, leap); err != nil {
+		return fail(err)
+	}
+	return f, nil
+}
+
+// gongheMonthDayOfYear is the inverse of dayOfGongheYear.
+func gongheMonthDayOfYear(doy int) (m, d int) {
+	doy--
+	m = doy / 61 * 2
+	d = doy % 61
+	if d >= 30 {
+		m++
+		d -= 30
+	}
+	return m + 1, d + 1
+}
+
+// parseInt parses a decimal number of min to max digits at the beginning of
+// s, max 0 means unlimited.
+func parseInt(s string, min, max int, signed bool) (n int, rest string, ok bool) {
+	i := 0
+	if signed && (strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+")) {
+		i++
+	}
+	j := i
+	for j < len(s) && s[j] >= '0' && s[j] <= '9' && (max == 0 || j-i < max) {
+		j++
+	}
+	if j-i < min {
+		return 0, s, false
+	}
+	n, err := strconv.Atoi(s[:j])
+	if err != nil {
+		return 0, s, false
+	}
+	return n, s[j:], true
+}
+
+func parseChineseDigits(s string) (n int, rest string, ok bool) {
+	var b strings.Builder
+	rest = s
+	if strings.HasPrefix(rest, "-") {
+		b.WriteByte('-')
+		rest = rest[1:]
+	}
+	for {
+		i := chineseDigitIndex(rest)
+		if i < 0 {
+			break
+		}
+		b.WriteByte(byte('0' + i))
+		rest = rest[len(chineseDigits[i]):]
+	}
+	n, err := strconv.Atoi(b.String())
+	if err != nil {
+		return 0, s, false
+	}
+	return n, rest, true
+}
+
+func chineseDigitIndex(s string) int {
+	for i, c := range chineseDigits[:10] {
+		if strings.HasPrefix(s, c) {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseChineseNumber parses a number in [1, 99] such as 二十一.
+func parseChineseNumber(s string) (n int, rest string, ok bool) {
+	// try the longest name first
+	for n := 99; n >= 1; n-- {
+		if name := chineseNumber(n); strings.HasPrefix(s, name) {
+			return n, s[len(name):], true
+		}
+	}
+	return 0, s, false
+}
+
+func cutPrefix(s, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
+
+// skipOneOf skips the longest one of the n names at the beginning of s.
+func skipOneOf(s string, n int, name func(int) string) (rest string, ok bool) {
+	longest := -1
+	for i := 0; i < n; i++ {
+		if x := name(i); strings.HasPrefix(s, x) && len(x) > longest {
+			longest = len(x)
+		}
+	}
+	if longest < 0 {
+		return s, false
+	}
+	return s[longest:], true
+}
+
+// Format returns g formatted by layout, see LayoutISO for the tokens.
+func (g GongheDate) Format(layout string) string {
+	return formatGonghe(gongheFields{g.Year, g.Month, g.Day, g.JD(), false}, layout)
+}
+
+// ParseGongheDate parses value formatted by layout, see LayoutISO for the
+// tokens. The month and day are 1 if they are absent.
+func ParseGongheDate(layout, value string) (GongheDate, error) {
+	f, err := parseGonghe(layout, value, false)
+	if err != nil {
+		return GongheDate{}, err
+	}
+	return GongheDate{f.year, f.month, f.day}, nil
+}
+
+// Format returns g formatted by layout, see LayoutISO for the tokens.
+func (g GHCDate) Format(layout string) string {
+	return formatGonghe(gongheFields{g.Year, g.Month, g.Day, g.JD(), true}, layout)
+}
+
+// ParseGHCDate parses value formatted by layout, see LayoutISO for the tokens.
+// The month and day are 1 if they are absent.
+func ParseGHCDate(layout, value string) (GHCDate, error) {
+	f, err := parseGonghe(layout, value, true)
+	if err != nil {
+		return GHCDate{}, err
+	}
+	return GHCDate{f.year, f.month, f.day}, nil
+}
@@ -0,0 +1,114 @@
+package zcal_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/tzengyuxio/zcal"
+)
+
+func TestGongheDateFormat(t *testing.T) {
+	// 西曆 2017-02-03 星期五
+	y, m, d := WesternCalendarToGongheCalendar(2017, 2, 3)
+	g := NewGongheDate(y, m, d)
+	for _, pair := range []struct {
+		layout, value string
+	}{
+		{LayoutISO, "2858-01-03"},
+		{LayoutEra, "2858-01-03 GH"},
+		{LayoutChinese, "共和二八五八年一月三日"},
+		{"Y/M/D", "2858/1/3"},
+		{"Y-NNN", "2858-003"},
+		{"YYYYMMDD", "28580103"},
+		{"YYYYNNN", "2858003"},
+		{"Y N", "2858 3"},
+		{"CY年CM月CD日 GZ日 WW", "二八五八年一月三日 " + WesternCalendarToStemBranch(2017, 2, 3) + "日 星期五"},
+		{"W, D \\M M Y", "Friday, 3 M 1 2858"},
+	} {
+		assert.Equal(t, pair.value, g.Format(pair.layout), "For layout %q", pair.layout)
+		p, err := ParseGongheDate(pair.layout, pair.value)
+		assert.NoError(t, err, "For layout %q", pair.layout)
+		assert.Equal(t, g, p, "For layout %q", pair.layout)
+	}
+
+	assert.Equal(t, "0001-01-01", NewGongheDate(1, 1, 1).Format(LayoutISO))
+	assert.Equal(t, "-0001-02-05", NewGongheDate(-1, 2, 5).Format(LayoutISO))
+	assert.Equal(t, "共和二一年二月二十一日", NewGongheDate(21, 2, 21).Format("EECY年CM月CD日"))
+	assert.Equal(t, "2860-366", NewGongheDate(2860, 12, 31).Format("Y-NNN"))
+
+	p, err := ParseGongheDate(LayoutISO, "-0001-02-05")
+	assert.NoError(t, err)
+	assert.Equal(t, NewGongheDate(-1, 2, 5), p)
+	p, err = ParseGongheDate("Y", "2858")
+	assert.NoError(t, err)
+	assert.Equal(t, NewGongheDate(2858, 1, 1), p)
+
+	// compact layouts round trip, and Y is not limited to 4 digits
+	for _, pair := range []struct {
+		layout string
+		g      GongheDate
+	}{
+		{"YYYYMMDD", NewGongheDate(1, 2, 3)},
+		{"YYYYMMDD", NewGongheDate(-1, 12, 30)},
+		{"YYYYNNN", NewGongheDate(2860, 12, 31)},
+		{"YYYYNNN", NewGongheDate(-841, 1, 1)},
+		{"Y-MM-DD", NewGongheDate(12858, 1, 3)},
+	} {
+		s := pair.g.Format(pair.layout)
+		p, err := ParseGongheDate(pair.layout, s)
+		assert.NoError(t, err, "For %q", s)
+		assert.Equal(t, pair.g, p, "For %q", s)
+	}
+	assert.Equal(t, "-08410101", NewGongheDate(-841, 1, 1).Format("YYYYMMDD"))
+}
+
+func TestGHCDateFormat(t *testing.T) {
+	y, m, d := WesternCalendarToGHC(2017, 2, 3)
+	g := NewGHCDate(y, m, d)
+	s := g.Format(LayoutEra)
+	assert.Equal(t, g.Format(LayoutISO)+" GHC", s)
+	p, err := ParseGHCDate(LayoutEra, s)
+	assert.NoError(t, err)
+	assert.Equal(t, g, p)
+
+	// GHC year label 3 is a leap year but 2 is not
+	_, err = ParseGHCDate("Y-NNN", "3-366")
+	assert.NoError(t, err)
+	_, err = ParseGHCDate("Y-NNN", "2-366")
+	assert.True(t, errors.Is(err, ErrInvalidDay))
+
+	// the era must match
+	_, err = ParseGHCDate(LayoutEra, "2857-12-30 GH")
+	assert.True(t, errors.Is(err, ErrLayout))
+	_, err = ParseGongheDate(LayoutEra, "2857-12-30 GHC")
+	assert.True(t, errors.Is(err, ErrLayout))
+}
+
+func TestParseGongheDateError(t *testing.T) {
+	for _, pair := range []struct {
+		layout, value string
+		err           error
+	}{
+		{LayoutISO, "2857-1-30", ErrLayout},
+		{LayoutISO, "857-01-30", ErrLayout},
+		{LayoutISO, "12857-01-30", ErrLayout},
+		{"YYYYMMDD", "2857130", ErrLayout},
+		{"YYYYMMDD", "285701300", ErrLayout},
+		{LayoutISO, "2857-01-30x", ErrLayout},
+		{LayoutISO, "2857/01/30", ErrLayout},
+		{"M-D", "01-30", ErrLayout},
+		{LayoutISO, "2857-13-01", ErrInvalidMonth},
+		{LayoutISO, "2857-02-32", ErrInvalidDay},
+		{LayoutISO, "2857-12-31", ErrInvalidDay},
+		{"Y-N", "2857-0", ErrInvalidDay},
+		{"Y-N M-D", "2857-32 01-02", ErrLayout},
+		{"CY年CM月", "二八五七年十三月", ErrInvalidMonth},
+		{"Y W", "2857 Fri", ErrLayout},
+	} {
+		_, err := ParseGongheDate(pair.layout, pair.value)
+		assert.True(t, errors.Is(err, pair.err), "For %q expected %v got %v", pair.value, pair.err, err)
+		var pe *ParseError
+		assert.True(t, errors.As(err, &pe), "For %q", pair.value)
+	}
+}
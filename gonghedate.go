@@ -0,0 +1,212 @@
+package zcal
+
+import "fmt"
+
+// GongheDate is a date in the Gonghe calendar (共和曆), which has 4/100/500
+// leap rule.
+//
+// The odd months have 30 days and the even months have 31 days, except the
+// 12th month which has 31 days in leap years only.
+type GongheDate struct {
+	Year, Month, Day int
+}
+
+// NewGongheDate returns the Gonghe date of year y, month m and day d.
+func NewGongheDate(y, m, d int) GongheDate {
+	return GongheDate{y, m, d}
+}
+
+// GongheDateFromJD returns the Gonghe date of the given Julian date.
+func GongheDateFromJD(jd float64) GongheDate {
+	y, m, d, _ := JDToGongheCalendar(jd)
+	return GongheDate{y, m, d}
+}
+
+// JD returns the Julian date at the beginning of the day.
+func (g GongheDate) JD() float64 {
+	return GongheCalendarToJD(g.Year, g.Month, g.Day)
+}
+
+// IsLeapYear returns true if the year of g is a leap year.
+func (g GongheDate) IsLeapYear() bool {
+	return LeapYearGonghe(g.Year)
+}
+
+// DaysInMonth returns the number of days in the month of g.
+func (g GongheDate) DaysInMonth() int {
+	return daysInGongheMonth(g.Month, g.IsLeapYear())
+}
+
+// DayOfYear returns the day of year of g, in the range [1, 366].
+func (g GongheDate) DayOfYear() int {
+	return dayOfGongheYear(g.Month, g.Day)
+}
+
+// AddDays returns the date n days after g, n could be negative.
+func (g GongheDate) AddDays(n int) GongheDate {
+	return GongheDateFromJD(g.JD() + float64(n))
+}
+
+// AddMonths returns the date n months after g. The day is clamped to the last
+// day of the resulting month, e.g. 5-02-31 plus one month is 5-03-30.
+func (g GongheDate) AddMonths(n int) GongheDate {
+	y, m := addMonths(g.Year, g.Month, n)
+	d := g.Day
+	if dm := daysInGongheMonth(m, LeapYearGonghe(y)); d > dm {
+		d = dm
+	}
+	return GongheDate{y, m, d}
+}
+
+// AddYears returns the date n years after g. The 31st day of the 12th month
+// is clamped to the 30th in a common year.
+func (g GongheDate) AddYears(n int) GongheDate {
+	return GongheDate{g.Year + n, g.Month, g.Day}.AddMonths(0)
+}
+
+// Sub returns the number of days from u to g.
+func (g GongheDate) Sub(u GongheDate) int {
+	return int(g.JD() - u.JD())
+}
+
+// Before reports whether g is before u.
+func (g GongheDate) Before(u GongheDate) bool {
+	return compareDate(g.Year, g.Month, g.Day, u.Year, u.Month, u.Day) < 0
+}
+
+// After reports whether g is after u.
+func (g GongheDate) After(u GongheDate) bool {
+	return compareDate(g.Year, g.Month, g.Day, u.Year, u.Month, u.Day) > 0
+}
+
+// Equal reports whether g and u are the same date.
+func (g GongheDate) Equal(u GongheDate) bool {
+	return g == u
+}
+
+func (g GongheDate) String() string {
+	return fmt.Sprintf("%d-%02d-%02d", g.Year, g.Month, g.Day)
+}
+
+// GHCDate is a date in the Gonghe calendar with 128-leap-rule, the months are
+// the same as GongheDate.
+//
+// Years are counted from the Gonghe zero year (JDOfGongheZeroDay), so the year
+// y is the (y+1)-th year of the 128-year cycle and it is a leap year if
+// LeapYearGHC(y+1) is true.
+type GHCDate struct {
+	Year, Month, Day int
+}
+
+// NewGHCDate returns the GHC date of year y, month m and day d.
+func NewGHCDate(y, m, d int) GHCDate {
+	return GHCDate{y, m, d}
+}
+
+// GHCDateFromJD returns the GHC date of the given Julian date.
+func GHCDateFromJD(jd float64) GHCDate {
+	y, m, d, _ := JDToGHC(jd)
+	return GHCDate{y, m, d}
+}
+
+// JD returns the Julian date at the beginning of the day.
+func (g GHCDate) JD() float64 {
+	return GHCToJD(g.Year, g.Month, g.Day)
+}
+
+// IsLeapYear returns true if the year of g is a leap year.
+func (g GHCDate) IsLeapYear() bool {
+	return LeapYearGHC(g.Year + 1)
+}
+
+// DaysInMonth returns the number of days in the month of g.
+func (g GHCDate) DaysInMonth() int {
+	return daysInGongheMonth(g.Month, g.IsLeapYear())
+}
+
+// DayOfYear returns the day of year of g, in the range [1, 366].
+func (g GHCDate) DayOfYear() int {
+	return dayOfGongheYear(g.Month, g.Day)
+}
+
+// AddDays returns the date n days after g, n could be negative.
+func (g GHCDate) AddDays(n int) GHCDate {
+	return GHCDateFromJD(g.JD() + float64(n))
+}
+
+// AddMonths returns the date n months after g. The day is clamped to the last
+// day of the resulting month.
+func (g GHCDate) AddMonths(n int) GHCDate {
+	y, m := addMonths(g.Year, g.Month, n)
+	d := g.Day
+	if dm := daysInGongheMonth(m, LeapYearGHC(y+1)); d > dm {
+		d = dm
+	}
+	return GHCDate{y, m, d}
+}
+
+// AddYears returns the date n years after g. The 31st day of the 12th month
+// is clamped to the 30th in a common year.
+func (g GHCDate) AddYears(n int) GHCDate {
+	return GHCDate{g.Year + n, g.Month, g.Day}.AddMonths(0)
+}
+
+// Sub returns the number of days from u to g.
+func (g GHCDate) Sub(u GHCDate) int {
+	return int(g.JD() - u.JD())
+}
+
+// Before reports whether g is before u.
+func (g GHCDate) Before(u GHCDate) bool {
+	return compareDate(g.Year, g.Month, g.Day, u.Year, u.Month, u.Day) < 0
+}
+
+// After reports whether g is after u.
+func (g GHCDate) After(u GHCDate) bool {
+	return compareDate(g.Year, g.Month, g.Day, u.Year, u.Month, u.Day) > 0
+}
+
+// Equal reports whether g and u are the same date.
+func (g GHCDate) Equal(u GHCDate) bool {
+	return g == u
+}
+
+func (g GHCDate) String() string {
+	return fmt.Sprintf("%d-%02d-%02d", g.Year, g.Month, g.Day)
+}
+
+// daysInGongheMonth returns the number of days of month m, which is shared by
+// both of the Gonghe calendars.
+func daysInGongheMonth(m int, leap bool) int {
+	switch {
+	case m == 12 && !leap:
+		return 30
+	case m%2 == 0:
+		return 31
+	}
+	return 30
+}
+
+func dayOfGongheYear(m, d int) int {
+	m--
+	return m*30 + m/2 + d
+}
+
+// addMonths adds n months to year y month m and normalizes the month into
+// [1, 12].
+func addMonths(y, m, n int) (int, int) {
+	m += n - 1
+	y += floorDiv(m, 12)
+	m -= floorDiv(m, 12) * 12
+	return y, m + 1
+}
+
+func compareDate(y1, m1, d1, y2, m2, d2 int) int {
+	switch {
+	case y1 != y2:
+		return y1 - y2
+	case m1 != m2:
+		return m1 - m2
+	}
+	return d1 - d2
+}
@@ -0,0 +1,138 @@
+package zcal_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/tzengyuxio/zcal"
+)
+
+func TestGongheDateFromJD(t *testing.T) {
+	for _, pair := range []struct {
+		date GongheDate
+		jd   float64
+	}{
+		{NewGongheDate(-499, 1, 1), JDOfGongheFirstDay - 182621.0},
+		{NewGongheDate(-3, 1, 1), JDOfGongheFirstDay - 1461.0},
+		{NewGongheDate(-1, 12, 30), JDOfGongheFirstDay - 367.0},
+		{NewGongheDate(0, 12, 31), JDOfGongheFirstDay - 1.0},
+		{NewGongheDate(1, 1, 1), JDOfGongheFirstDay},
+		{NewGongheDate(1, 2, 1), JDOfGongheFirstDay + 30.0},
+		{NewGongheDate(501, 1, 1), JDOfGongheFirstDay + 182621.0},
+		{NewGongheDate(2225, 11, 19), JDOfShuodanDongzhi},
+	} {
+		assert.Equal(t, pair.date, GongheDateFromJD(pair.jd), "For JD %.1f", pair.jd)
+		assert.Equal(t, pair.jd, pair.date.JD(), "For date %s", pair.date)
+	}
+}
+
+func TestGongheDateDaysInMonth(t *testing.T) {
+	for _, pair := range []struct {
+		date GongheDate
+		days int
+	}{
+		{NewGongheDate(1, 1, 1), 30},
+		{NewGongheDate(1, 2, 1), 31},
+		{NewGongheDate(1, 11, 1), 30},
+		{NewGongheDate(1, 12, 1), 30},
+		{NewGongheDate(4, 12, 1), 31},
+		{NewGongheDate(100, 12, 1), 30},
+		{NewGongheDate(500, 12, 1), 31},
+	} {
+		assert.Equal(t, pair.days, pair.date.DaysInMonth(), "For date %s", pair.date)
+	}
+}
+
+func TestGongheDateDayOfYear(t *testing.T) {
+	for _, pair := range []struct {
+		date GongheDate
+		day  int
+	}{
+		{NewGongheDate(1, 1, 1), 1},
+		{NewGongheDate(1, 1, 30), 30},
+		{NewGongheDate(1, 2, 1), 31},
+		{NewGongheDate(1, 3, 1), 62},
+		{NewGongheDate(1, 12, 30), 365},
+		{NewGongheDate(4, 12, 31), 366},
+	} {
+		assert.Equal(t, pair.day, pair.date.DayOfYear(), "For date %s", pair.date)
+	}
+}
+
+func TestGongheDateArithmetic(t *testing.T) {
+	d := NewGongheDate(4, 12, 31)
+	assert.Equal(t, NewGongheDate(5, 1, 1), d.AddDays(1))
+	assert.Equal(t, NewGongheDate(4, 12, 1), d.AddDays(-30))
+	assert.Equal(t, NewGongheDate(2225, 11, 19), NewGongheDate(1, 1, 1).AddDays(int(JDOfShuodanDongzhi-JDOfGongheFirstDay)))
+
+	// negative years
+	for _, y := range []int{-1, -3, -4, -100, -400, -500} {
+		g := NewGongheDate(y, 1, 1)
+		assert.Equal(t, g, g.AddDays(0))
+		assert.Equal(t, NewGongheDate(y-1, 12, NewGongheDate(y-1, 12, 1).DaysInMonth()), g.AddDays(-1))
+		days := 365
+		if g.IsLeapYear() {
+			days = 366
+		}
+		assert.Equal(t, days, NewGongheDate(y+1, 1, 1).Sub(g), "For year %d", y)
+	}
+
+	assert.Equal(t, NewGongheDate(5, 1, 30), d.AddMonths(1))
+	assert.Equal(t, NewGongheDate(4, 11, 30), d.AddMonths(-1))
+	assert.Equal(t, NewGongheDate(3, 12, 30), d.AddMonths(-12))
+	assert.Equal(t, NewGongheDate(0, 12, 31), d.AddMonths(-48))
+	assert.Equal(t, NewGongheDate(0, 1, 30), d.AddMonths(-59))
+	assert.Equal(t, NewGongheDate(-1, 12, 30), d.AddMonths(-60))
+
+	assert.Equal(t, NewGongheDate(5, 12, 30), d.AddYears(1))
+	assert.Equal(t, NewGongheDate(8, 12, 31), d.AddYears(4))
+	assert.Equal(t, NewGongheDate(-96, 12, 31), d.AddYears(-100))
+
+	assert.Equal(t, 1, NewGongheDate(5, 1, 1).Sub(d))
+	assert.Equal(t, -1461, NewGongheDate(1, 1, 1).Sub(NewGongheDate(5, 1, 1)))
+	assert.Equal(t, 182621, NewGongheDate(501, 1, 1).Sub(NewGongheDate(1, 1, 1)))
+}
+
+func TestGongheDateCompare(t *testing.T) {
+	a, b := NewGongheDate(4, 12, 31), NewGongheDate(5, 1, 1)
+	assert.True(t, a.Before(b))
+	assert.False(t, b.Before(a))
+	assert.True(t, b.After(a))
+	assert.False(t, a.After(a))
+	assert.True(t, a.Equal(NewGongheDate(4, 12, 31)))
+	assert.False(t, a.Equal(b))
+	assert.True(t, NewGongheDate(-1, 12, 30).Before(NewGongheDate(0, 1, 1)))
+	assert.Equal(t, "-1-12-30", NewGongheDate(-1, 12, 30).String())
+	assert.Equal(t, "2225-11-19", NewGongheDate(2225, 11, 19).String())
+}
+
+func TestGHCDateRoundTrip(t *testing.T) {
+	assert.Equal(t, NewGHCDate(0, 1, 1), GHCDateFromJD(JDOfGongheZeroDay))
+	assert.Equal(t, JDOfGongheZeroDay, NewGHCDate(0, 1, 1).JD())
+
+	prev := GHCDateFromJD(JDOfGongheZeroDay - 300*365.25)
+	for jd := JDOfGongheZeroDay - 300*365.25 + 1; jd < JDOfGongheZeroDay+300*365.25; jd++ {
+		g := GHCDateFromJD(jd)
+		assert.Equal(t, jd, g.JD(), "For date %s", g)
+		assert.Equal(t, g, prev.AddDays(1), "For date %s", prev)
+		if g.Day == 1 {
+			assert.Equal(t, prev.DaysInMonth(), prev.Day, "For date %s", prev)
+		}
+		prev = g
+	}
+}
+
+func TestGHCDateArithmetic(t *testing.T) {
+	d := NewGHCDate(3, 12, 31) // the 4th year of the cycle is a leap year
+	assert.True(t, d.IsLeapYear())
+	assert.False(t, NewGHCDate(127, 1, 1).IsLeapYear())
+	assert.Equal(t, 366, d.DayOfYear())
+	assert.Equal(t, NewGHCDate(4, 1, 1), d.AddDays(1))
+	assert.Equal(t, NewGHCDate(4, 12, 30), d.AddYears(1))
+	assert.Equal(t, NewGHCDate(7, 12, 31), d.AddYears(4))
+	assert.Equal(t, NewGHCDate(3, 2, 28), NewGHCDate(2, 12, 28).AddMonths(2))
+	assert.Equal(t, 46751, NewGHCDate(128, 1, 1).Sub(NewGHCDate(0, 1, 1)))
+	assert.Equal(t, 46751, NewGHCDate(0, 1, 1).Sub(NewGHCDate(-128, 1, 1)))
+	assert.True(t, NewGHCDate(-1, 12, 30).Before(NewGHCDate(0, 1, 1)))
+	assert.True(t, NewGHCDate(0, 1, 1).After(NewGHCDate(-1, 12, 30)))
+}
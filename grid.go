@@ -0,0 +1,125 @@
+package zcal
+
+import "math"
+
+// GridDay is a day in a month grid, with the date in every calendar.
+type GridDay struct {
+	JD           float64 // the beginning of the day
+	InMonth      bool    // false for the days of the adjacent months
+	Weekday      int     // 0 is Sunday, see JDToWeekday
+	Western      [3]int  // year, month and day in Western calendar
+	Gonghe       GongheDate
+	Lunar        LunarDate
+	Ganzhi       string // stem-branch of the day
+	SolarTerm    SolarTerm
+	HasSolarTerm bool // true if SolarTerm is in the day
+	MoonPhase    MoonPhase
+	HasMoonPhase bool         // true if MoonPhase is in the day
+	Moon         Illumination // the moon at the local noon
+}
+
+// MonthGrid is a month arranged in weeks from Sunday to Saturday. The days of
+// the adjacent months fill the first and the last week.
+type MonthGrid struct {
+	Calendar    string // Gonghe, Western or Lunar
+	Year, Month int
+	IsLeap      bool // leap month of Chinese lunar calendar
+	Weeks       [][7]GridDay
+}
+
+// GongheMonthGrid returns the grid of a month in Gonghe calendar. The solar
+// terms are marked in the local days of time zone tz. A nil e means the
+// default ephemeris.
+func GongheMonthGrid(e Ephemeris, year, month int, tz float64) (MonthGrid, error) {
+	if err := ValidateGongheCalendar(year, month, 1); err != nil {
+		return MonthGrid{}, err
+	}
+	days := daysInGongheMonth(month, LeapYearGonghe(year))
+	first := GongheCalendarToJD(year, month, 1)
+	return MonthGrid{"Gonghe", year, month, false, monthWeeks(e, first, days, tz)}, nil
+}
+
+// WesternMonthGrid returns the grid of a month in Western calendar, see
+// GongheMonthGrid. The month of the Gregorian reform is shorter.
+func WesternMonthGrid(e Ephemeris, year, month int, tz float64) (MonthGrid, error) {
+	w := DefaultWesternCalendar
+	if err := w.Validate(year, month, 1); err != nil {
+		return MonthGrid{}, err
+	}
+	ny, nm := year, month+1
+	if nm > 12 {
+		ny, nm = year+1, 1
+		if ny == 0 {
+			ny = 1
+		}
+	}
+	first := w.ToJD(year, month, 1)
+	days := int(w.ToJD(ny, nm, 1) - first)
+	return MonthGrid{"Western", year, month, false, monthWeeks(e, first, days, tz)}, nil
+}
+
+// LunarMonthGrid returns the grid of a month in Chinese lunar calendar, the
+// solar terms are marked in UTC+8.
+func LunarMonthGrid(e Ephemeris, year, month int, isLeap bool) (MonthGrid, error) {
+	first, err := ChineseLunarToJD(e, year, month, isLeap, 1)
+	if err != nil {
+		return MonthGrid{}, err
+	}
+	days := ChineseLunarMonthDays(e, year, month, isLeap)
+	return MonthGrid{"Lunar", year, month, isLeap, monthWeeks(e, first, days, lunarTZ)}, nil
+}
+
+// monthWeeks returns the weeks of the month which begins at first and has
+// the given days.
+func monthWeeks(e Ephemeris, first float64, days int, tz float64) [][7]GridDay {
+	start := first - float64(JDToWeekday(first))
+	end := first + float64(days)
+	n := int(math.Ceil((end - start) / 7))
+
+	terms := solarTermDays(e, start, start+float64(n*7), tz)
+	phases := make(map[int]MoonPhase)
+	for _, p := range MoonPhases(start, start+float64(n*7), tz) {
+		phases[localDay(p.JD)] = p.Phase
+	}
+	weeks := make([][7]GridDay, n)
+	for i := range weeks {
+		for j := range weeks[i] {
+			jd := start + float64(i*7+j)
+			y, m, d := DefaultWesternCalendar.FromJD(jd)
+			term, ok := terms[localDay(jd)]
+			phase, hasPhase := phases[localDay(jd)]
+			weeks[i][j] = GridDay{
+				JD:           jd,
+				InMonth:      jd >= first && jd < end,
+				Weekday:      j,
+				Western:      [3]int{y, m, d},
+				Gonghe:       GongheDateFromJD(jd),
+				Lunar:        LunarDateFromJD(e, jd),
+				Ganzhi:       JDToStemBranch(jd),
+				SolarTerm:    term,
+				HasSolarTerm: ok,
+				MoonPhase:    phase,
+				HasMoonPhase: hasPhase,
+				Moon:         MoonIllumination(e, jd+.5, tz),
+			}
+		}
+	}
+	return weeks
+}
+
+// solarTermDays returns the solar terms between from and to by the Julian day
+// numbers of their local days.
+func solarTermDays(e Ephemeris, from, to, tz float64) map[int]SolarTerm {
+	y0, _, _, _ := JDToGregorianCalendar(from)
+	y1, _, _, _ := JDToGregorianCalendar(to)
+	days := make(map[int]SolarTerm)
+	// the solar year of y begins at 立春 of y
+	for y := y0 - 1; y <= y1; y++ {
+		for _, s := range SolarTerms(e, y, tz) {
+			if s.JD >= from && s.JD < to {
+				days[localDay(s.JD)] = s.Term
+			}
+		}
+	}
+	return days
+}
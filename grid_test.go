@@ -0,0 +1,135 @@
+package zcal_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/tzengyuxio/zcal"
+)
+
+func TestWesternMonthGrid(t *testing.T) {
+	e := DefaultEphemeris()
+
+	g, err := WesternMonthGrid(e, 2017, 2, 8)
+	assert.NoError(t, err)
+	assert.Equal(t, "Western", g.Calendar)
+	assert.Len(t, g.Weeks, 5)
+
+	// 2017-02-01 is Wednesday
+	first := g.Weeks[0][3]
+	assert.True(t, first.InMonth)
+	assert.False(t, g.Weeks[0][2].InMonth)
+	assert.Equal(t, [3]int{2017, 2, 1}, first.Western)
+	assert.Equal(t, 3, first.Weekday)
+	assert.Equal(t, GregorianCalendarToJD(2017, 2, 1), first.JD)
+	assert.Equal(t, [3]int{2017, 1, 29}, g.Weeks[0][0].Western)
+
+	lichun := g.Weeks[0][5]
+	assert.Equal(t, [3]int{2017, 2, 3}, lichun.Western)
+	assert.True(t, lichun.HasSolarTerm)
+	assert.Equal(t, LiChun, lichun.SolarTerm)
+	assert.Equal(t, WesternCalendarToStemBranch(2017, 2, 3), lichun.Ganzhi)
+	y, m, d := WesternCalendarToGongheCalendar(2017, 2, 3)
+	assert.Equal(t, NewGongheDate(y, m, d), lichun.Gonghe)
+	assert.Equal(t, LunarDate{2017, 1, false, 7}, lichun.Lunar)
+
+	days, terms, phases := 0, 0, 0
+	for _, week := range g.Weeks {
+		for _, day := range week {
+			if day.InMonth {
+				days++
+			}
+			if day.HasSolarTerm {
+				terms++
+			}
+			if day.HasMoonPhase {
+				phases++
+			}
+		}
+	}
+	assert.Equal(t, 28, days)
+	assert.Equal(t, 2, terms)
+	assert.Equal(t, 4, phases)
+
+	// 望 2017-02-11 08:33 (UTC+8)
+	full := g.Weeks[1][6]
+	assert.Equal(t, [3]int{2017, 2, 11}, full.Western)
+	assert.True(t, full.HasMoonPhase)
+	assert.Equal(t, FullMoon, full.MoonPhase)
+	assert.InDelta(t, 1, full.Moon.Fraction, .01)
+	assert.Equal(t, MoonIllumination(e, full.JD+.5, 8), full.Moon)
+
+	// 1582-10-04 is followed by 1582-10-15
+	g, err = WesternMonthGrid(e, 1582, 10, 8)
+	assert.NoError(t, err)
+	days = 0
+	for _, week := range g.Weeks {
+		for _, day := range week {
+			if day.InMonth {
+				days++
+			}
+		}
+	}
+	assert.Equal(t, 21, days)
+
+	_, err = WesternMonthGrid(e, 2017, 13, 8)
+	assert.True(t, errors.Is(err, ErrInvalidMonth))
+	_, err = WesternMonthGrid(e, 0, 1, 8)
+	assert.True(t, errors.Is(err, ErrYearZero))
+}
+
+func TestGongheMonthGrid(t *testing.T) {
+	e := DefaultEphemeris()
+
+	for _, pair := range []struct {
+		year, month, days int
+	}{
+		{2858, 1, 30},
+		{2858, 2, 31},
+		{2858, 12, 30},
+		{2860, 12, 31},
+	} {
+		g, err := GongheMonthGrid(e, pair.year, pair.month, 8)
+		assert.NoError(t, err)
+		var dates []GongheDate
+		for _, week := range g.Weeks {
+			for _, day := range week {
+				assert.Equal(t, JDToWeekday(day.JD), day.Weekday)
+				if day.InMonth {
+					dates = append(dates, day.Gonghe)
+				}
+			}
+		}
+		assert.Len(t, dates, pair.days, "For %d-%d", pair.year, pair.month)
+		assert.Equal(t, NewGongheDate(pair.year, pair.month, 1), dates[0])
+		assert.Equal(t, NewGongheDate(pair.year, pair.month, pair.days), dates[len(dates)-1])
+	}
+
+	_, err := GongheMonthGrid(e, 2858, 0, 8)
+	assert.True(t, errors.Is(err, ErrInvalidMonth))
+}
+
+func TestLunarMonthGrid(t *testing.T) {
+	e := DefaultEphemeris()
+
+	// 2017 年閏六月, 2017-07-23 to 2017-08-21
+	g, err := LunarMonthGrid(e, 2017, 6, true)
+	assert.NoError(t, err)
+	assert.True(t, g.IsLeap)
+	var days []GridDay
+	for _, week := range g.Weeks {
+		for _, day := range week {
+			if day.InMonth {
+				days = append(days, day)
+			}
+		}
+	}
+	assert.Len(t, days, 30)
+	assert.Equal(t, [3]int{2017, 7, 23}, days[0].Western)
+	assert.Equal(t, LunarDate{2017, 6, true, 1}, days[0].Lunar)
+	assert.Equal(t, [3]int{2017, 8, 21}, days[29].Western)
+
+	_, err = LunarMonthGrid(e, 2018, 6, true)
+	assert.True(t, errors.Is(err, ErrInvalidMonth))
+}
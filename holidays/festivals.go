@@ -0,0 +1,47 @@
+package holidays
+
+import "github.com/tzengyuxio/zcal"
+
+// Festival is a Chinese traditional festival on a lunar date.
+type Festival struct {
+	Key  string // the Key of the Holiday, e.g. "zhongqiu"
+	Name string // the name in traditional Chinese
+	// Month and Day are the lunar date, day 0 means the last day of the year,
+	// i.e. the day before 正月初一 of the next year, which is in the leap
+	// month if month has one.
+	Month, Day int
+}
+
+// Festivals are the traditional festivals of the Chinese lunar calendar, 清明
+// is not one of them since it is a solar term.
+var Festivals = []Festival{
+	{"chunjie", "春節", 1, 1},
+	{"yuanxiao", "元宵節", 1, 15},
+	{"duanwu", "端午節", 5, 5},
+	{"qixi", "七夕", 7, 7},
+	{"zhongyuan", "中元節", 7, 15},
+	{"zhongqiu", "中秋節", 8, 15},
+	{"chongyang", "重陽節", 9, 9},
+	{"laba", "臘八節", 12, 8},
+	{"chuxi", "除夕", 12, 0},
+}
+
+// JD returns the Julian date of the festival in the lunar year. A nil e means
+// the default ephemeris, and the error is the one of zcal.ChineseLunarToJD.
+func (f Festival) JD(e zcal.Ephemeris, year int) (float64, error) {
+	if f.Day == 0 {
+		jd, err := zcal.ChineseLunarToJD(e, year+1, 1, false, 1)
+		return jd - 1, err
+	}
+	return zcal.ChineseLunarToJD(e, year, f.Month, false, f.Day)
+}
+
+// festival returns the dates of the festival of the key in Festivals.
+func festival(key string) dateFunc {
+	for _, f := range Festivals {
+		if f.Key == key {
+			return lunar(f.Month, f.Day)
+		}
+	}
+	panic("holidays: unknown festival " + key)
+}
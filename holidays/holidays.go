@@ -0,0 +1,223 @@
+// Package holidays computes the Chinese traditional festivals and the public
+// holidays of Taiwan, mainland China, Hong Kong and Singapore.
+//
+// The lunar festivals follow the Chinese lunar calendar of zcal, and 清明 is
+// the day of the solar term in UTC+8. The substitute days off of the
+// holidays on weekends are computed by the rules of each region, but the
+// holidays announced every year are not, e.g. the adjusted working days of
+// mainland China and Taiwan, and the Islamic and Hindu holidays of Singapore.
+package holidays
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/soniakeys/meeus/easter"
+	"github.com/tzengyuxio/zcal"
+)
+
+// Region is a region by its ISO 3166-1 alpha-2 code.
+type Region string
+
+// The supported regions.
+const (
+	Taiwan    Region = "TW"
+	China     Region = "CN"
+	HongKong  Region = "HK"
+	Singapore Region = "SG"
+)
+
+// Regions are the supported regions.
+var Regions = []Region{Taiwan, China, HongKong, Singapore}
+
+// ParseRegion parses the code of a region, case insensitive. The second
+// return value is false if the region is not supported.
+func ParseRegion(s string) (Region, bool) {
+	r := Region(strings.ToUpper(s))
+	_, ok := regionRules[r]
+	return r, ok
+}
+
+// Errors returned by List.
+var (
+	ErrRegion = errors.New("holidays: unknown region")
+	ErrYear   = errors.New("holidays: year out of range")
+)
+
+// Holiday is a festival or a public holiday of a day.
+type Holiday struct {
+	// Key is the identifier of the holiday, e.g. "chunjie", "chunjie-2" for
+	// the second day, and "national-day-observed" for the substitute day.
+	Key     string
+	Name    string // the name in the language of the region
+	English string
+	JD      float64 // the Julian date of the day
+	// Public is true for a day off by law, otherwise it is an observance.
+	Public bool
+	// Observed is true for a substitute day off of a holiday on a weekend.
+	Observed bool
+}
+
+// Date returns the Gregorian date of the holiday.
+func (h Holiday) Date() (year, month, day int) {
+	year, month, day, _ = zcal.JDToGregorianCalendar(h.JD)
+	return
+}
+
+// context is the year of the computation.
+type context struct {
+	year int
+	e    zcal.Ephemeris
+}
+
+// dateFunc returns the dates of a rule around the year, they are filtered
+// by the Gregorian year later.
+type dateFunc func(c context) []float64
+
+// rule is a holiday of a region, effective from year from to year to, zero
+// means unbounded.
+type rule struct {
+	key, name, english string
+	date               dateFunc
+	public             bool
+	from, to           int
+}
+
+// lunar returns the dates of lunar month m day d, day 0 is the last day of
+// the year as Festival.
+func lunar(m, d int) dateFunc {
+	f := Festival{Month: m, Day: d}
+	return func(c context) []float64 {
+		var jds []float64
+		for _, y := range []int{c.year - 1, c.year} {
+			if jd, err := f.JD(c.e, y); err == nil {
+				jds = append(jds, jd)
+			}
+		}
+		return jds
+	}
+}
+
+// solarTerm returns the date of the solar term in UTC+8.
+func solarTerm(t zcal.SolarTerm) dateFunc {
+	return func(c context) []float64 {
+		return []float64{day(zcal.SolarTermJD(c.e, c.year, t, 8))}
+	}
+}
+
+// fixed returns the Gregorian date month m day d.
+func fixed(m, d int) dateFunc {
+	return func(c context) []float64 {
+		return []float64{zcal.GregorianCalendarToJD(c.year, m, d)}
+	}
+}
+
+// easterDay returns the date n days after Easter Sunday.
+func easterDay(n int) dateFunc {
+	return func(c context) []float64 {
+		m, d := easter.Gregorian(c.year)
+		return []float64{zcal.GregorianCalendarToJD(c.year, m, d) + float64(n)}
+	}
+}
+
+// day returns the Julian date of the beginning of the day of jd.
+func day(jd float64) float64 {
+	return math.Floor(jd+.5) - .5
+}
+
+// List returns the holidays of the region in the Gregorian year ordered by
+// date, from 1912 to 9999. A nil e means the default ephemeris.
+func List(region Region, year int, e zcal.Ephemeris) ([]Holiday, error) {
+	rules, ok := regionRules[region]
+	if !ok {
+		return nil, ErrRegion
+	}
+	if year < 1912 || year > 9999 {
+		return nil, ErrYear
+	}
+	c := context{year, e}
+	first := zcal.GregorianCalendarToJD(year, 1, 1)
+	next := zcal.GregorianCalendarToJD(year+1, 1, 1)
+
+	var holidays []Holiday
+	for _, r := range rules {
+		if (r.from != 0 && year < r.from) || (r.to != 0 && year > r.to) {
+			continue
+		}
+		for _, jd := range r.date(c) {
+			if jd >= first && jd < next {
+				holidays = append(holidays, Holiday{Key: r.key, Name: r.name, English: r.english, JD: jd, Public: r.public})
+			}
+		}
+	}
+	if fix, ok := regionFixes[region]; ok {
+		fix(holidays)
+	}
+	sortHolidays(holidays)
+	if substitute, ok := regionSubstitutes[region]; ok {
+		holidays = append(holidays, substitute(holidays)...)
+		sortHolidays(holidays)
+	}
+	return holidays, nil
+}
+
+func sortHolidays(holidays []Holiday) {
+	sort.SliceStable(holidays, func(i, j int) bool {
+		return holidays[i].JD < holidays[j].JD
+	})
+}
+
+// weekday returns the weekday of jd, 0 is Sunday.
+func weekday(jd float64) int {
+	return zcal.JDToWeekday(jd)
+}
+
+// daysOff returns the set of the days of the public holidays.
+func daysOff(holidays []Holiday) map[float64]bool {
+	off := make(map[float64]bool)
+	for _, h := range holidays {
+		if h.Public {
+			off[h.JD] = true
+		}
+	}
+	return off
+}
+
+// observed returns the substitute day off of h at jd.
+func observed(h Holiday, jd float64) Holiday {
+	return Holiday{
+		Key:      h.Key + "-observed",
+		Name:     h.Name,
+		English:  h.English + " (observed)",
+		JD:       jd,
+		Public:   true,
+		Observed: true,
+	}
+}
+
+// substituteSunday gives the next day which is not a public holiday for a
+// public holiday on Sunday, or on the same day as another public holiday, as
+// the rules of Hong Kong and Singapore.
+func substituteSunday(holidays []Holiday) []Holiday {
+	off := daysOff(holidays)
+	seen := make(map[float64]bool)
+	var result []Holiday
+	for _, h := range holidays {
+		if !h.Public {
+			continue
+		}
+		if weekday(h.JD) != 0 && !seen[h.JD] {
+			seen[h.JD] = true
+			continue
+		}
+		jd := h.JD + 1
+		for off[jd] {
+			jd++
+		}
+		off[jd] = true
+		result = append(result, observed(h, jd))
+	}
+	return result
+}
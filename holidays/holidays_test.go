@@ -0,0 +1,139 @@
+package holidays_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tzengyuxio/zcal"
+	. "github.com/tzengyuxio/zcal/holidays"
+)
+
+// daysOff returns the dates of the public holidays in "MM-DD".
+func daysOff(t *testing.T, region Region, year int) []string {
+	holidays, err := List(region, year, nil)
+	assert.NoError(t, err)
+	var dates []string
+	for _, h := range holidays {
+		if h.Public {
+			_, m, d := h.Date()
+			dates = append(dates, fmt.Sprintf("%02d-%02d", m, d))
+		}
+	}
+	return dates
+}
+
+func TestList(t *testing.T) {
+	for _, pair := range []struct {
+		region Region
+		year   int
+		dates  []string
+	}{
+		{Taiwan, 2017, []string{"01-01", "01-02", "01-27", "01-28", "01-29", "01-30", "01-31", "02-01",
+			"02-28", "04-03", "04-04", "05-30", "10-04", "10-10"}},
+		{Taiwan, 2025, []string{"01-01", "01-28", "01-29", "01-30", "01-31", "02-28", "04-03", "04-04",
+			"05-01", "05-30", "05-31", "09-28", "09-29", "10-06", "10-10", "10-24", "10-25", "12-25"}},
+		{China, 2017, []string{"01-01", "01-28", "01-29", "01-30", "04-04", "05-01", "05-30",
+			"10-01", "10-02", "10-03", "10-04"}},
+		{China, 2010, []string{"01-01", "02-13", "02-14", "02-15", "04-05", "05-01", "06-16",
+			"09-22", "10-01", "10-02", "10-03"}},
+		{China, 2025, []string{"01-01", "01-28", "01-29", "01-30", "01-31", "04-04", "05-01", "05-02",
+			"05-31", "10-01", "10-02", "10-03", "10-06"}},
+		{HongKong, 2017, []string{"01-01", "01-02", "01-28", "01-29", "01-30", "01-31", "04-04",
+			"04-14", "04-15", "04-17", "05-01", "05-03", "05-30", "07-01", "10-01", "10-02",
+			"10-05", "10-28", "12-25", "12-26"}},
+		// 清明 is Easter Monday
+		{HongKong, 2010, []string{"01-01", "02-14", "02-15", "02-16", "02-17", "04-02", "04-03",
+			"04-05", "04-05", "04-06", "05-01", "05-21", "06-16", "07-01", "09-23", "10-01",
+			"10-16", "12-25", "12-26", "12-27"}},
+		{Singapore, 2017, []string{"01-01", "01-02", "01-28", "01-29", "01-30", "04-14", "05-01",
+			"08-09", "12-25"}},
+	} {
+		assert.Equal(t, pair.dates, daysOff(t, pair.region, pair.year), "For %s %d", pair.region, pair.year)
+	}
+}
+
+func TestListFestivals(t *testing.T) {
+	holidays, err := List(China, 2017, nil)
+	assert.NoError(t, err)
+	dates := make(map[string]string)
+	for _, h := range holidays {
+		_, m, d := h.Date()
+		dates[h.Key] = fmt.Sprintf("%02d-%02d", m, d)
+	}
+	for key, date := range map[string]string{
+		"chuxi":     "01-27",
+		"chunjie":   "01-28",
+		"yuanxiao":  "02-11",
+		"qingming":  "04-04",
+		"duanwu":    "05-30",
+		"zhongqiu":  "10-04",
+		"chongyang": "10-28",
+	} {
+		assert.Equal(t, date, dates[key], "For %s", key)
+	}
+
+	// 除夕 is 臘月廿九 in 2025 and 2026, whose 臘月 are 29 days
+	for _, pair := range []struct {
+		year int
+		date string
+	}{
+		{2017, "01-27"}, {2019, "02-04"}, {2025, "01-28"}, {2026, "02-16"},
+	} {
+		holidays, err := List(Taiwan, pair.year, nil)
+		assert.NoError(t, err)
+		for _, h := range holidays {
+			if h.Key == "chuxi" {
+				_, m, d := h.Date()
+				assert.Equal(t, pair.date, fmt.Sprintf("%02d-%02d", m, d), "For %d", pair.year)
+			}
+		}
+	}
+}
+
+func TestFestivals(t *testing.T) {
+	dates := make(map[string]string)
+	for _, f := range Festivals {
+		jd, err := f.JD(nil, 2017)
+		assert.NoError(t, err)
+		y, m, d, _ := zcal.JDToGregorianCalendar(jd)
+		dates[f.Key] = fmt.Sprintf("%d-%02d-%02d", y, m, d)
+	}
+	assert.Equal(t, map[string]string{
+		"chunjie":   "2017-01-28",
+		"yuanxiao":  "2017-02-11",
+		"duanwu":    "2017-05-30",
+		"qixi":      "2017-08-28",
+		"zhongyuan": "2017-09-05",
+		"zhongqiu":  "2017-10-04",
+		"chongyang": "2017-10-28",
+		"laba":      "2018-01-24",
+		"chuxi":     "2018-02-15",
+	}, dates)
+}
+
+func TestListObserved(t *testing.T) {
+	holidays, err := List(Taiwan, 2017, nil)
+	assert.NoError(t, err)
+	var observed []string
+	for _, h := range holidays {
+		if h.Observed {
+			observed = append(observed, h.Key)
+			assert.True(t, h.Public)
+		}
+	}
+	assert.Equal(t, []string{"new-year-observed", "chunjie-observed", "chunjie-2-observed"}, observed)
+}
+
+func TestListErrors(t *testing.T) {
+	_, err := List("JP", 2017, nil)
+	assert.Equal(t, ErrRegion, err)
+	_, err = List(Taiwan, 1911, nil)
+	assert.Equal(t, ErrYear, err)
+
+	r, ok := ParseRegion("hk")
+	assert.True(t, ok)
+	assert.Equal(t, HongKong, r)
+	_, ok = ParseRegion("jp")
+	assert.False(t, ok)
+}
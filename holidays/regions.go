@@ -0,0 +1,157 @@
+package holidays
+
+import "github.com/tzengyuxio/zcal"
+
+// regionRules are the holidays of the regions, the traditional festivals
+// which are not days off are listed as observances.
+var regionRules = map[Region][]rule{
+	Taiwan: {
+		{key: "new-year", name: "中華民國開國紀念日", english: "Republic Day", date: fixed(1, 1), public: true},
+		{key: "chuxi", name: "除夕", english: "Lunar New Year's Eve", date: festival("chuxi"), public: true},
+		{key: "chunjie", name: "春節", english: "Lunar New Year", date: festival("chunjie"), public: true},
+		{key: "chunjie-2", name: "春節", english: "Lunar New Year (day 2)", date: lunar(1, 2), public: true},
+		{key: "chunjie-3", name: "春節", english: "Lunar New Year (day 3)", date: lunar(1, 3), public: true},
+		{key: "yuanxiao", name: "元宵節", english: "Lantern Festival", date: festival("yuanxiao")},
+		{key: "peace-memorial", name: "和平紀念日", english: "Peace Memorial Day", date: fixed(2, 28), public: true, from: 1997},
+		{key: "childrens-day", name: "兒童節", english: "Children's Day", date: fixed(4, 4), public: true},
+		{key: "qingming", name: "民族掃墓節", english: "Tomb Sweeping Day", date: solarTerm(zcal.QingMing), public: true},
+		{key: "labour-day", name: "勞動節", english: "Labour Day", date: fixed(5, 1), public: true, from: 2025},
+		{key: "duanwu", name: "端午節", english: "Dragon Boat Festival", date: festival("duanwu"), public: true},
+		{key: "zhongqiu", name: "中秋節", english: "Mid-Autumn Festival", date: festival("zhongqiu"), public: true},
+		{key: "teachers-day", name: "孔子誕辰紀念日", english: "Teachers' Day", date: fixed(9, 28), public: true, from: 2025},
+		{key: "chongyang", name: "重陽節", english: "Double Ninth Festival", date: festival("chongyang")},
+		{key: "national-day", name: "國慶日", english: "National Day", date: fixed(10, 10), public: true},
+		{key: "retrocession-day", name: "臺灣光復暨金門古寧頭大捷紀念日", english: "Retrocession Day", date: fixed(10, 25), public: true, from: 2025},
+		{key: "constitution-day", name: "行憲紀念日", english: "Constitution Day", date: fixed(12, 25), public: true, from: 2025},
+	},
+	China: {
+		{key: "new-year", name: "元旦", english: "New Year's Day", date: fixed(1, 1), public: true},
+		{key: "chuxi", name: "除夕", english: "Spring Festival Eve", date: festival("chuxi"), public: true, from: 2008, to: 2013},
+		{key: "chuxi", name: "除夕", english: "Spring Festival Eve", date: festival("chuxi"), to: 2007},
+		{key: "chuxi", name: "除夕", english: "Spring Festival Eve", date: festival("chuxi"), from: 2014, to: 2024},
+		{key: "chuxi", name: "除夕", english: "Spring Festival Eve", date: festival("chuxi"), public: true, from: 2025},
+		{key: "chunjie", name: "春节", english: "Spring Festival", date: festival("chunjie"), public: true},
+		{key: "chunjie-2", name: "春节", english: "Spring Festival (day 2)", date: lunar(1, 2), public: true},
+		{key: "chunjie-3", name: "春节", english: "Spring Festival (day 3)", date: lunar(1, 3), public: true, to: 2007},
+		{key: "chunjie-3", name: "春节", english: "Spring Festival (day 3)", date: lunar(1, 3), public: true, from: 2014},
+		{key: "yuanxiao", name: "元宵节", english: "Lantern Festival", date: festival("yuanxiao")},
+		{key: "qingming", name: "清明节", english: "Qingming Festival", date: solarTerm(zcal.QingMing), to: 2007},
+		{key: "qingming", name: "清明节", english: "Qingming Festival", date: solarTerm(zcal.QingMing), public: true, from: 2008},
+		{key: "labour-day", name: "劳动节", english: "Labour Day", date: fixed(5, 1), public: true},
+		{key: "labour-day-2", name: "劳动节", english: "Labour Day (day 2)", date: fixed(5, 2), public: true, to: 2007},
+		{key: "labour-day-3", name: "劳动节", english: "Labour Day (day 3)", date: fixed(5, 3), public: true, to: 2007},
+		{key: "labour-day-2", name: "劳动节", english: "Labour Day (day 2)", date: fixed(5, 2), public: true, from: 2025},
+		{key: "duanwu", name: "端午节", english: "Dragon Boat Festival", date: festival("duanwu"), to: 2007},
+		{key: "duanwu", name: "端午节", english: "Dragon Boat Festival", date: festival("duanwu"), public: true, from: 2008},
+		{key: "zhongqiu", name: "中秋节", english: "Mid-Autumn Festival", date: festival("zhongqiu"), to: 2007},
+		{key: "zhongqiu", name: "中秋节", english: "Mid-Autumn Festival", date: festival("zhongqiu"), public: true, from: 2008},
+		{key: "chongyang", name: "重阳节", english: "Double Ninth Festival", date: festival("chongyang")},
+		{key: "national-day", name: "国庆节", english: "National Day", date: fixed(10, 1), public: true, from: 1949},
+		{key: "national-day-2", name: "国庆节", english: "National Day (day 2)", date: fixed(10, 2), public: true, from: 1949},
+		{key: "national-day-3", name: "国庆节", english: "National Day (day 3)", date: fixed(10, 3), public: true, from: 1999},
+	},
+	HongKong: {
+		{key: "new-year", name: "一月一日", english: "The first day of January", date: fixed(1, 1), public: true},
+		{key: "chuxi", name: "農曆年初一前夕", english: "Lunar New Year's Eve", date: festival("chuxi")},
+		{key: "chunjie", name: "農曆年初一", english: "Lunar New Year's Day", date: festival("chunjie"), public: true},
+		{key: "chunjie-2", name: "農曆年初二", english: "The second day of Lunar New Year", date: lunar(1, 2), public: true},
+		{key: "chunjie-3", name: "農曆年初三", english: "The third day of Lunar New Year", date: lunar(1, 3), public: true},
+		{key: "yuanxiao", name: "元宵節", english: "Lantern Festival", date: festival("yuanxiao")},
+		{key: "qingming", name: "清明節", english: "Ching Ming Festival", date: solarTerm(zcal.QingMing), public: true},
+		{key: "good-friday", name: "耶穌受難節", english: "Good Friday", date: easterDay(-2), public: true},
+		{key: "holy-saturday", name: "耶穌受難節翌日", english: "The day following Good Friday", date: easterDay(-1), public: true},
+		{key: "easter-monday", name: "復活節星期一", english: "Easter Monday", date: easterDay(1), public: true},
+		{key: "labour-day", name: "勞動節", english: "Labour Day", date: fixed(5, 1), public: true, from: 1999},
+		{key: "buddhas-birthday", name: "佛誕", english: "The Birthday of the Buddha", date: lunar(4, 8), public: true, from: 1999},
+		{key: "duanwu", name: "端午節", english: "Tuen Ng Festival", date: festival("duanwu"), public: true},
+		{key: "hksar-day", name: "香港特別行政區成立紀念日", english: "Hong Kong Special Administrative Region Establishment Day", date: fixed(7, 1), public: true, from: 1997},
+		{key: "zhongqiu", name: "中秋節", english: "Mid-Autumn Festival", date: festival("zhongqiu")},
+		{key: "day-after-zhongqiu", name: "中秋節翌日", english: "The day following the Chinese Mid-Autumn Festival", date: lunar(8, 16), public: true},
+		{key: "national-day", name: "國慶日", english: "National Day", date: fixed(10, 1), public: true, from: 1997},
+		{key: "chongyang", name: "重陽節", english: "Chung Yeung Festival", date: festival("chongyang"), public: true},
+		{key: "christmas", name: "聖誕節", english: "Christmas Day", date: fixed(12, 25), public: true},
+		{key: "boxing-day", name: "聖誕節後第一個周日", english: "The first weekday after Christmas Day", date: fixed(12, 26), public: true},
+	},
+	Singapore: {
+		{key: "new-year", name: "元旦", english: "New Year's Day", date: fixed(1, 1), public: true},
+		{key: "chuxi", name: "除夕", english: "Chinese New Year's Eve", date: festival("chuxi")},
+		{key: "chunjie", name: "农历新年", english: "Chinese New Year", date: festival("chunjie"), public: true},
+		{key: "chunjie-2", name: "农历新年", english: "Chinese New Year (day 2)", date: lunar(1, 2), public: true},
+		{key: "yuanxiao", name: "元宵节", english: "Lantern Festival", date: festival("yuanxiao")},
+		{key: "qingming", name: "清明节", english: "Qingming Festival", date: solarTerm(zcal.QingMing)},
+		{key: "good-friday", name: "耶稣受难日", english: "Good Friday", date: easterDay(-2), public: true},
+		{key: "labour-day", name: "劳动节", english: "Labour Day", date: fixed(5, 1), public: true},
+		{key: "duanwu", name: "端午节", english: "Dragon Boat Festival", date: festival("duanwu")},
+		{key: "national-day", name: "国庆日", english: "National Day", date: fixed(8, 9), public: true, from: 1966},
+		{key: "zhongqiu", name: "中秋节", english: "Mid-Autumn Festival", date: festival("zhongqiu")},
+		{key: "chongyang", name: "重阳节", english: "Double Ninth Festival", date: festival("chongyang")},
+		{key: "christmas", name: "圣诞节", english: "Christmas Day", date: fixed(12, 25), public: true},
+	},
+}
+
+// regionFixes adjust the dates of the holidays before the substitute days
+// are computed.
+var regionFixes = map[Region]func([]Holiday){
+	Taiwan: fixTaiwan,
+}
+
+// regionSubstitutes return the substitute days off of the holidays.
+var regionSubstitutes = map[Region]func([]Holiday) []Holiday{
+	Taiwan:    substituteTaiwan,
+	HongKong:  substituteSunday,
+	Singapore: substituteSunday,
+}
+
+// fixTaiwan moves 兒童節 to the previous day if it is the same day as 民族掃墓節,
+// or to the next day if the previous day is Wednesday.
+func fixTaiwan(holidays []Holiday) {
+	qingming := 0.
+	for _, h := range holidays {
+		if h.Key == "qingming" {
+			qingming = h.JD
+		}
+	}
+	for i, h := range holidays {
+		if h.Key == "childrens-day" && h.JD == qingming {
+			if weekday(h.JD) == 4 {
+				holidays[i].JD++
+			} else {
+				holidays[i].JD--
+			}
+		}
+	}
+}
+
+// substituteTaiwan gives the substitute days off of Taiwan: the previous
+// workday for a public holiday on Saturday, and the next workday for one on
+// Sunday. The days of Lunar New Year on weekends are always substituted by
+// the next workdays.
+func substituteTaiwan(holidays []Holiday) []Holiday {
+	off := daysOff(holidays)
+	workday := func(jd float64) bool {
+		wd := weekday(jd)
+		return wd != 0 && wd != 6 && !off[jd]
+	}
+	var result []Holiday
+	for _, h := range holidays {
+		wd := weekday(h.JD)
+		if !h.Public || (wd != 0 && wd != 6) {
+			continue
+		}
+		step := 1.
+		if wd == 6 && h.Key != "chuxi" && !isChunjie(h.Key) {
+			step = -1
+		}
+		jd := h.JD + step
+		for !workday(jd) {
+			jd += step
+		}
+		off[jd] = true
+		result = append(result, observed(h, jd))
+	}
+	return result
+}
+
+func isChunjie(key string) bool {
+	return key == "chunjie" || key == "chunjie-2" || key == "chunjie-3"
+}
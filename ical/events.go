@@ -0,0 +1,185 @@
+// Package ical exports the Gonghe calendar, the solar terms, the moon phases
+// and the Chinese festivals as iCalendar (RFC 5545) feeds.
+package ical
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tzengyuxio/zcal"
+	"github.com/tzengyuxio/zcal/holidays"
+)
+
+// Kind is a kind of events, the kinds could be combined by bitwise or.
+type Kind int
+
+// The kinds of events.
+const (
+	GongheMonths   Kind = 1 << iota // the first days of Gonghe months
+	GongheLeapDays                  // the 12-31 of Gonghe leap years (LeapYearGonghe)
+	GHCLeapDays                     // the 12-31 of GHC leap years (LeapYearGHC)
+	SolarTerms                      // the instants of the 24 solar terms
+	MoonPhases                      // the instants of new and full moons
+	Festivals                       // the Chinese festivals of lunar calendar, see holidays.Festivals
+
+	AllKinds = GongheMonths | GongheLeapDays | GHCLeapDays | SolarTerms | MoonPhases | Festivals
+)
+
+var kindNames = map[Kind]string{
+	GongheMonths:   "Gonghe month",
+	GongheLeapDays: "Gonghe leap day",
+	GHCLeapDays:    "GHC leap day",
+	SolarTerms:     "Solar term",
+	MoonPhases:     "Moon phase",
+	Festivals:      "Festival",
+}
+
+func (k Kind) String() string {
+	if s, ok := kindNames[k]; ok {
+		return s
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Options of a feed.
+type Options struct {
+	// FromYear and ToYear are the range of years, inclusive, in the Gregorian
+	// calendar which is used by iCalendar. They must be in [1, 9999].
+	FromYear, ToYear int
+	// Kinds of the events, zero means AllKinds.
+	Kinds Kind
+	// Location of the timed events, nil means UTC.
+	Location *time.Location
+	// Ephemeris for the solar terms and the lunar calendar, nil means the
+	// default one.
+	Ephemeris zcal.Ephemeris
+	// Domain is the right hand side of UIDs, "zcal" if empty.
+	Domain string
+	// Name is the name of the calendar (X-WR-CALNAME), optional.
+	Name string
+	// Stamp is the DTSTAMP of events, the current time if zero.
+	Stamp time.Time
+}
+
+// ErrYearRange means the years of Options are out of [1, 9999] or FromYear is
+// after ToYear.
+var ErrYearRange = errors.New("ical: invalid year range")
+
+// Event is an event of a feed.
+type Event struct {
+	UID     string // stable among feeds of the same Domain
+	Kind    Kind
+	Summary string
+	// Start is the instant of a timed event, or the date at midnight UTC of
+	// an all-day event.
+	Start  time.Time
+	AllDay bool
+}
+
+// Events returns the events of the options ordered by the start.
+func Events(o Options) ([]Event, error) {
+	if o.FromYear < 1 || o.ToYear > 9999 || o.FromYear > o.ToYear {
+		return nil, ErrYearRange
+	}
+	if o.Kinds == 0 {
+		o.Kinds = AllKinds
+	}
+	if o.Location == nil {
+		o.Location = time.UTC
+	}
+	domain := o.Domain
+	if domain == "" {
+		domain = "zcal"
+	}
+
+	var events []Event
+	add := func(kind Kind, id, summary string, start time.Time, allDay bool) {
+		y := start.Year()
+		if !allDay {
+			y = start.In(o.Location).Year()
+		}
+		if y < o.FromYear || y > o.ToYear {
+			return
+		}
+		events = append(events, Event{id + "@" + domain, kind, summary, start, allDay})
+	}
+	// the days in range, and one more year at both ends for the calendars
+	// whose years do not begin at January 1
+	jd0 := zcal.GregorianCalendarToJD(o.FromYear-1, 1, 1)
+	jd1 := zcal.GregorianCalendarToJD(o.ToYear+2, 1, 1)
+
+	if o.Kinds&GongheMonths != 0 {
+		g := zcal.GongheDateFromJD(jd0)
+		for g = zcal.NewGongheDate(g.Year, g.Month, 1); g.JD() < jd1; g = g.AddMonths(1) {
+			add(GongheMonths, fmt.Sprintf("gonghe-month-%d-%02d", g.Year, g.Month),
+				g.Format("EEY年M月"), date(g.JD()), true)
+		}
+	}
+	if o.Kinds&GongheLeapDays != 0 {
+		for y := zcal.GongheDateFromJD(jd0).Year; y <= zcal.GongheDateFromJD(jd1).Year; y++ {
+			if zcal.LeapYearGonghe(y) {
+				add(GongheLeapDays, fmt.Sprintf("gonghe-leap-%d", y),
+					fmt.Sprintf("共和%d年閏日", y), date(zcal.NewGongheDate(y, 12, 31).JD()), true)
+			}
+		}
+	}
+	if o.Kinds&GHCLeapDays != 0 {
+		for y := zcal.GHCDateFromJD(jd0).Year; y <= zcal.GHCDateFromJD(jd1).Year; y++ {
+			if g := zcal.NewGHCDate(y, 12, 31); g.IsLeapYear() {
+				add(GHCLeapDays, fmt.Sprintf("ghc-leap-%d", y),
+					fmt.Sprintf("共和%d年閏日 (GHC)", y), date(g.JD()), true)
+			}
+		}
+	}
+	if o.Kinds&SolarTerms != 0 {
+		for y := o.FromYear - 1; y <= o.ToYear; y++ {
+			for _, s := range zcal.SolarTerms(o.Ephemeris, y, 0) {
+				add(SolarTerms, fmt.Sprintf("solarterm-%d-%02d", y, int(s.Term)),
+					s.Term.String(), instant(s.JD), false)
+			}
+		}
+	}
+	if o.Kinds&MoonPhases != 0 {
+		k0 := int((float64(o.FromYear) - 2001) * 12.3685)
+		k1 := int((float64(o.ToYear) - 1999) * 12.3685)
+		for k := k0; k <= k1; k++ {
+			add(MoonPhases, fmt.Sprintf("newmoon-%d", k), "朔",
+				instant(zcal.MoonPhaseJD(zcal.Lunation(k), zcal.NewMoon, 0)), false)
+			add(MoonPhases, fmt.Sprintf("fullmoon-%d", k), "望",
+				instant(zcal.MoonPhaseJD(zcal.Lunation(k), zcal.FullMoon, 0)), false)
+		}
+	}
+	if o.Kinds&Festivals != 0 {
+		for y := o.FromYear - 1; y <= o.ToYear; y++ {
+			for _, f := range holidays.Festivals {
+				jd, err := f.JD(o.Ephemeris, y)
+				if err != nil {
+					continue
+				}
+				add(Festivals, fmt.Sprintf("festival-%d-%s", y, f.Key), f.Name, date(jd), true)
+			}
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].Start.Equal(events[j].Start) {
+			return events[i].Start.Before(events[j].Start)
+		}
+		return events[i].UID < events[j].UID
+	})
+	return events, nil
+}
+
+// date returns the Gregorian date of the day of jd at midnight UTC.
+func date(jd float64) time.Time {
+	y, m, d, _ := zcal.JDToGregorianCalendar(jd)
+	return time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+}
+
+// instant returns the time of jd, which is in UT, in UTC.
+func instant(jd float64) time.Time {
+	t, _ := zcal.JD(jd).ToTime(time.UTC)
+	return t
+}
@@ -0,0 +1,162 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const prodID = "-//tzengyuxio//zcal//EN"
+
+// Write writes the events of the options as a VCALENDAR feed. The timed
+// events are in the time zone of o.Location, whose VTIMEZONE is generated
+// from the transitions in the range of years.
+func Write(w io.Writer, o Options) error {
+	events, err := Events(o)
+	if err != nil {
+		return err
+	}
+	loc := o.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	stamp := o.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	b := bufio.NewWriter(w)
+	line := func(s string) { b.WriteString(fold(s)) }
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:" + prodID)
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	if o.Name != "" {
+		line("X-WR-CALNAME:" + escape(o.Name))
+	}
+	if loc != time.UTC {
+		from := time.Date(o.FromYear, 1, 1, 0, 0, 0, 0, loc)
+		to := time.Date(o.ToYear+1, 1, 1, 0, 0, 0, 0, loc)
+		for _, l := range vtimezone(loc, from, to) {
+			line(l)
+		}
+	}
+	for _, e := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + escape(e.UID))
+		line("DTSTAMP:" + stamp.UTC().Format("20060102T150405Z"))
+		if e.AllDay {
+			line("DTSTART;VALUE=DATE:" + e.Start.Format("20060102"))
+			line("DTEND;VALUE=DATE:" + e.Start.AddDate(0, 0, 1).Format("20060102"))
+		} else {
+			line("DTSTART" + dateTime(e.Start, loc))
+		}
+		line("SUMMARY:" + escape(e.Summary))
+		line("CATEGORIES:" + escape(e.Kind.String()))
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return b.Flush()
+}
+
+// dateTime returns the parameter and the value of a DATE-TIME property, e.g.
+// ";TZID=Asia/Taipei:20170203T233400" and ":20170203T153400Z".
+func dateTime(t time.Time, loc *time.Location) string {
+	if loc == time.UTC {
+		return ":" + t.UTC().Format("20060102T150405Z")
+	}
+	return ";TZID=" + paramValue(loc.String()) + ":" + t.In(loc).Format("20060102T150405")
+}
+
+// vtimezone returns the VTIMEZONE component of loc between from and to. Each
+// offset transition becomes an observance, the first one is the offset at
+// from.
+func vtimezone(loc *time.Location, from, to time.Time) []string {
+	lines := []string{"BEGIN:VTIMEZONE", "TZID:" + loc.String()}
+	observance := func(t time.Time, offsetFrom int) {
+		name, offset := t.Zone()
+		kind := "STANDARD"
+		if t.IsDST() {
+			kind = "DAYLIGHT"
+		}
+		lines = append(lines,
+			"BEGIN:"+kind,
+			// the onset in the local time before the transition
+			"DTSTART:"+t.In(time.FixedZone(name, offsetFrom)).Format("20060102T150405"),
+			"TZOFFSETFROM:"+utcOffset(offsetFrom),
+			"TZOFFSETTO:"+utcOffset(offset),
+			"TZNAME:"+escape(name),
+			"END:"+kind,
+		)
+	}
+
+	_, offset := from.Zone()
+	observance(from, offset)
+	for t := from; t.Before(to); {
+		next := t.AddDate(0, 0, 1)
+		if _, o := next.Zone(); o != offset {
+			// find the transition to the second
+			lo, hi := t, next
+			for hi.Sub(lo) > time.Second {
+				mid := lo.Add(hi.Sub(lo) / 2)
+				if _, o := mid.Zone(); o == offset {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			observance(hi, offset)
+			_, offset = hi.Zone()
+		}
+		t = next
+	}
+	return append(lines, "END:VTIMEZONE")
+}
+
+// utcOffset formats the offset in seconds as UTC-OFFSET, e.g. +0800.
+func utcOffset(offset int) string {
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	s := fmt.Sprintf("%c%02d%02d", sign, offset/3600, offset/60%60)
+	if offset%60 != 0 {
+		s += fmt.Sprintf("%02d", offset%60)
+	}
+	return s
+}
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// paramValue quotes a parameter value if it contains special characters.
+func paramValue(s string) string {
+	if strings.ContainsAny(s, `:;,`) {
+		return `"` + strings.Replace(s, `"`, "", -1) + `"`
+	}
+	return s
+}
+
+// fold folds a content line longer than 75 octets and ends it with CRLF.
+func fold(s string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range s {
+		l := utf8.RuneLen(r)
+		if n+l > 75 {
+			b.WriteString("\r\n ")
+			n = 1
+		}
+		b.WriteRune(r)
+		n += l
+	}
+	b.WriteString("\r\n")
+	return b.String()
+}
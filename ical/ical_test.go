@@ -0,0 +1,159 @@
+package ical_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tzengyuxio/zcal"
+	. "github.com/tzengyuxio/zcal/ical"
+)
+
+func eventsOf(t *testing.T, o Options) map[string]Event {
+	events, err := Events(o)
+	assert.NoError(t, err)
+	m := make(map[string]Event)
+	for i, e := range events {
+		_, dup := m[e.UID]
+		assert.False(t, dup, "Duplicated UID %s", e.UID)
+		m[e.UID] = e
+		if i > 0 {
+			assert.False(t, e.Start.Before(events[i-1].Start), "Events are not ordered at %s", e.UID)
+		}
+	}
+	return m
+}
+
+func TestEvents(t *testing.T) {
+	m := eventsOf(t, Options{FromYear: 2017, ToYear: 2017})
+
+	// 共和2858年1月 begins at 2017-02-01
+	e := m["gonghe-month-2858-01@zcal"]
+	assert.Equal(t, GongheMonths, e.Kind)
+	assert.Equal(t, "共和2858年1月", e.Summary)
+	assert.True(t, e.AllDay)
+	assert.Equal(t, time.Date(2017, 2, 1, 0, 0, 0, 0, time.UTC), e.Start)
+	months := 0
+	for _, e := range m {
+		if e.Kind == GongheMonths {
+			months++
+		}
+	}
+	assert.Equal(t, 12, months)
+
+	// 立春 2017-02-03 15:34 UTC
+	e = m["solarterm-2017-00@zcal"]
+	assert.Equal(t, "立春", e.Summary)
+	assert.False(t, e.AllDay)
+	assert.WithinDuration(t, time.Date(2017, 2, 3, 15, 34, 0, 0, time.UTC), e.Start, 2*time.Minute)
+	// 小寒 and 大寒 of solar year 2016 are in January 2017
+	assert.Contains(t, m, "solarterm-2016-22@zcal")
+	assert.NotContains(t, m, "solarterm-2017-22@zcal")
+
+	// 春節 2017-01-28, 中秋 2017-10-04, 除夕 2017-01-27
+	assert.Equal(t, time.Date(2017, 1, 28, 0, 0, 0, 0, time.UTC), m["festival-2017-chunjie@zcal"].Start)
+	assert.Equal(t, time.Date(2017, 10, 4, 0, 0, 0, 0, time.UTC), m["festival-2017-zhongqiu@zcal"].Start)
+	assert.Equal(t, time.Date(2017, 1, 27, 0, 0, 0, 0, time.UTC), m["festival-2016-chuxi@zcal"].Start)
+
+	// 除夕 of lunar year 1403 is the last day of 閏十二月
+	assert.Equal(t, 12, zcal.ChineseLunarLeapMonth(nil, 1403))
+	jd, err := zcal.ChineseLunarToJD(nil, 1403, 12, true, zcal.ChineseLunarMonthDays(nil, 1403, 12, true))
+	assert.NoError(t, err)
+	y, mo, d, _ := zcal.JDToGregorianCalendar(jd)
+	e = eventsOf(t, Options{FromYear: 1404, ToYear: 1404, Kinds: Festivals})["festival-1403-chuxi@zcal"]
+	assert.Equal(t, time.Date(y, time.Month(mo), d, 0, 0, 0, 0, time.UTC), e.Start)
+
+	// 12 or 13 new moons and full moons in a year
+	n := 0
+	for _, e := range m {
+		if e.Kind == MoonPhases {
+			n++
+		}
+	}
+	assert.True(t, n >= 24 && n <= 26, "%d moon phases", n)
+	for _, e := range m {
+		assert.Equal(t, 2017, e.Start.Year(), "For %s", e.UID)
+	}
+}
+
+func TestLeapDays(t *testing.T) {
+	m := eventsOf(t, Options{FromYear: 2000, ToYear: 2030, Kinds: GongheLeapDays | GHCLeapDays, Domain: "example.com"})
+	for uid, e := range m {
+		assert.True(t, strings.HasSuffix(uid, "@example.com"))
+		assert.True(t, e.AllDay)
+		if e.Kind == GongheLeapDays {
+			g := zcal.GongheDateFromJD(zcal.GregorianCalendarToJD(e.Start.Year(), int(e.Start.Month()), e.Start.Day()))
+			assert.Equal(t, [2]int{12, 31}, [2]int{g.Month, g.Day})
+			assert.True(t, zcal.LeapYearGonghe(g.Year))
+		} else {
+			g := zcal.GHCDateFromJD(zcal.GregorianCalendarToJD(e.Start.Year(), int(e.Start.Month()), e.Start.Day()))
+			assert.Equal(t, [2]int{12, 31}, [2]int{g.Month, g.Day})
+			assert.True(t, g.IsLeapYear())
+		}
+	}
+	assert.Len(t, m, 16)
+}
+
+func TestEventsError(t *testing.T) {
+	for _, o := range []Options{{FromYear: 0, ToYear: 1}, {FromYear: 2000, ToYear: 1999}, {FromYear: 9999, ToYear: 10000}} {
+		_, err := Events(o)
+		assert.Equal(t, ErrYearRange, err)
+		assert.Equal(t, ErrYearRange, Write(&bytes.Buffer{}, o))
+	}
+}
+
+func TestWrite(t *testing.T) {
+	taipei := time.FixedZone("CST", 8*60*60)
+	var b bytes.Buffer
+	err := Write(&b, Options{
+		FromYear: 2017, ToYear: 2017,
+		Kinds:    SolarTerms | Festivals,
+		Location: taipei,
+		Name:     "節氣, 節日",
+		Stamp:    time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+	s := b.String()
+	assert.True(t, strings.HasPrefix(s, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//tzengyuxio//zcal//EN\r\n"))
+	assert.True(t, strings.HasSuffix(s, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+	assert.Contains(t, s, "X-WR-CALNAME:節氣\\, 節日\r\n")
+	assert.Contains(t, s, "BEGIN:VTIMEZONE\r\nTZID:CST\r\nBEGIN:STANDARD\r\nDTSTART:20170101T000000\r\nTZOFFSETFROM:+0800\r\nTZOFFSETTO:+0800\r\nTZNAME:CST\r\nEND:STANDARD\r\nEND:VTIMEZONE\r\n")
+	assert.Contains(t, s, "UID:solarterm-2017-00@zcal\r\nDTSTAMP:20170101T000000Z\r\nDTSTART;TZID=CST:20170203T233")
+	assert.Contains(t, s, "UID:festival-2017-chunjie@zcal\r\nDTSTAMP:20170101T000000Z\r\nDTSTART;VALUE=DATE:20170128\r\nDTEND;VALUE=DATE:20170129\r\nSUMMARY:春節\r\nCATEGORIES:Festival\r\n")
+	assert.Equal(t, strings.Count(s, "BEGIN:VEVENT"), strings.Count(s, "END:VEVENT"))
+	for _, l := range strings.Split(s, "\r\n") {
+		assert.True(t, len(l) <= 75, "Line is too long: %q", l)
+	}
+
+	b.Reset()
+	assert.NoError(t, Write(&b, Options{FromYear: 2017, ToYear: 2017, Kinds: SolarTerms}))
+	assert.NotContains(t, b.String(), "VTIMEZONE")
+	assert.Contains(t, b.String(), "UID:solarterm-2017-00@zcal\r\n")
+	assert.Regexp(t, `DTSTART:20170203T153[45]\d\dZ`, b.String())
+}
+
+func TestWriteDaylightSaving(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database is unavailable")
+	}
+	var b bytes.Buffer
+	assert.NoError(t, Write(&b, Options{FromYear: 2017, ToYear: 2017, Kinds: SolarTerms, Location: ny}))
+	s := b.String()
+	assert.Contains(t, s, "BEGIN:DAYLIGHT\r\nDTSTART:20170312T020000\r\nTZOFFSETFROM:-0500\r\nTZOFFSETTO:-0400\r\nTZNAME:EDT\r\nEND:DAYLIGHT\r\n")
+	assert.Contains(t, s, "BEGIN:STANDARD\r\nDTSTART:20171105T020000\r\nTZOFFSETFROM:-0400\r\nTZOFFSETTO:-0500\r\nTZNAME:EST\r\nEND:STANDARD\r\n")
+	assert.Contains(t, s, "DTSTART;TZID=America/New_York:20170621T00")
+}
+
+func TestFold(t *testing.T) {
+	var b bytes.Buffer
+	assert.NoError(t, Write(&b, Options{FromYear: 2017, ToYear: 2017, Kinds: SolarTerms, Name: strings.Repeat("共和曆", 20)}))
+	s := b.String()
+	i := strings.Index(s, "X-WR-CALNAME:")
+	j := strings.Index(s[i:], "\r\nBEGIN:VEVENT")
+	folded := s[i : i+j]
+	assert.Equal(t, "X-WR-CALNAME:"+strings.Repeat("共和曆", 20), strings.Replace(folded, "\r\n ", "", -1))
+	assert.Contains(t, folded, "\r\n ")
+}
@@ -0,0 +1,235 @@
+// Package convert converts dates between calendars for the zcal commands.
+package convert
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tzengyuxio/zcal"
+)
+
+// Calendars are the calendars a date could be converted to.
+var Calendars = []string{"western", "gonghe", "ghc", "jd", "ganzhi", "lunar", "weekday"}
+
+// InputCalendars are the calendars a date could be parsed from.
+var InputCalendars = []string{"western", "gonghe", "ghc", "jd"}
+
+// ParseCalendars parses a comma separated list of Calendars.
+func ParseCalendars(s string) ([]string, error) {
+	var calendars []string
+	for _, c := range strings.Split(s, ",") {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if !Contains(Calendars, c) {
+			return nil, fmt.Errorf("unknown calendar %q", c)
+		}
+		calendars = append(calendars, c)
+	}
+	return calendars, nil
+}
+
+// Contains returns true if s is in a.
+func Contains(a []string, s string) bool {
+	for _, x := range a {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// The range of the Julian dates accepted by ParseDate, from the first day of
+// the Julian period, -4713-01-01, where the Western calendar conversions of
+// zcal begin, to the years supported by the lunar calendar and the solar
+// terms, see zcal.MaxYear.
+var (
+	MinJD = zcal.JulianCalendarToJD(-4712, 1, 1)
+	MaxJD = zcal.GregorianCalendarToJD(zcal.MaxYear, 1, 1)
+)
+
+// MinYear and MaxYear are the Western years of MinJD and MaxJD.
+const (
+	MinYear = -4713
+	MaxYear = zcal.MaxYear - 1
+)
+
+// ErrRange means the date is out of MinJD to MaxJD.
+var ErrRange = errors.New("date out of range")
+
+// ParseDate parses the date of calendar c and returns its Julian date. The
+// dates are written as Y-M-D, and a Western year before 1 AD is negative.
+// ErrRange is returned for the dates out of MinJD to MaxJD, including the
+// Julian dates NaN and infinity.
+func ParseDate(c, s string) (float64, error) {
+	jd, err := parseDate(c, s)
+	if err != nil {
+		return 0, err
+	}
+	// the comparisons are false for NaN
+	if !(jd >= MinJD && jd < MaxJD) {
+		return 0, fmt.Errorf("%w: %s", ErrRange, s)
+	}
+	return jd, nil
+}
+
+func parseDate(c, s string) (float64, error) {
+	switch strings.ToLower(c) {
+	case "western":
+		y, m, d, err := parseYMD(s)
+		if err != nil {
+			return 0, err
+		}
+		if err := zcal.DefaultWesternCalendar.Validate(y, m, d); err != nil {
+			return 0, err
+		}
+		return zcal.DefaultWesternCalendar.ToJD(y, m, d), nil
+	case "gonghe":
+		g, err := zcal.ParseGongheDate("Y-M-D", s)
+		if err != nil {
+			return 0, err
+		}
+		return g.JD(), nil
+	case "ghc":
+		g, err := zcal.ParseGHCDate("Y-M-D", s)
+		if err != nil {
+			return 0, err
+		}
+		return g.JD(), nil
+	case "jd":
+		jd, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid Julian date %q", s)
+		}
+		return jd, nil
+	}
+	return 0, fmt.Errorf("unknown calendar %q", c)
+}
+
+var errDateSyntax = errors.New("date is not in the form of Y-M-D")
+
+func parseYMD(s string) (y, m, d int, err error) {
+	sign := 1
+	if strings.HasPrefix(s, "-") {
+		sign, s = -1, s[1:]
+	}
+	f := strings.Split(s, "-")
+	if len(f) != 3 {
+		return 0, 0, 0, errDateSyntax
+	}
+	var n [3]int
+	for i := range f {
+		if n[i], err = strconv.Atoi(f[i]); err != nil || strings.HasPrefix(f[i], "+") {
+			return 0, 0, 0, errDateSyntax
+		}
+	}
+	return sign * n[0], n[1], n[2], nil
+}
+
+// Result is the conversions of a date, the absent calendars are omitted.
+type Result struct {
+	Western string   `json:"western,omitempty"`
+	Gonghe  string   `json:"gonghe,omitempty"`
+	GHC     string   `json:"ghc,omitempty"`
+	JD      *float64 `json:"jd,omitempty"`
+	Ganzhi  string   `json:"ganzhi,omitempty"`
+	Lunar   *Lunar   `json:"lunar,omitempty"`
+	Weekday string   `json:"weekday,omitempty"`
+}
+
+// Lunar is a Chinese lunar calendar date. Year is in the Western year
+// numbering as the Western dates, i.e. a year before 1 AD is negative and
+// there is no year 0.
+type Lunar struct {
+	Year  int    `json:"year"`
+	Month int    `json:"month"`
+	Leap  bool   `json:"leap"`
+	Day   int    `json:"day"`
+	Text  string `json:"text"`
+}
+
+// NewLunar returns the Lunar of l, whose year is in astronomical year
+// numbering, or nil if l is the zero LunarDate of a Julian date out of the
+// supported years, see zcal.JDToChineseLunar.
+func NewLunar(l zcal.LunarDate) *Lunar {
+	if l.Month == 0 {
+		return nil
+	}
+	l.Year = WesternYear(l.Year)
+	return &Lunar{l.Year, l.Month, l.IsLeap, l.Day, l.String()}
+}
+
+// WesternYear returns the Western year of astronomical year y, e.g. -841 for
+// -840.
+func WesternYear(y int) int {
+	if y <= 0 {
+		return y - 1
+	}
+	return y
+}
+
+// AstronomicalYear returns the astronomical year of Western year y, which is
+// the inverse of WesternYear.
+func AstronomicalYear(y int) int {
+	if y < 0 {
+		return y + 1
+	}
+	return y
+}
+
+// Western formats the Western calendar date of jd.
+func Western(jd float64) string {
+	y, m, d := zcal.DefaultWesternCalendar.FromJD(jd)
+	return fmt.Sprintf("%d-%02d-%02d", y, m, d)
+}
+
+// Convert converts jd to the calendars.
+func Convert(jd float64, calendars []string) Result {
+	var r Result
+	for _, c := range calendars {
+		switch c {
+		case "western":
+			r.Western = Western(jd)
+		case "gonghe":
+			r.Gonghe = zcal.GongheDateFromJD(jd).String()
+		case "ghc":
+			r.GHC = zcal.GHCDateFromJD(jd).String()
+		case "jd":
+			jd := jd
+			r.JD = &jd
+		case "ganzhi":
+			r.Ganzhi = zcal.JDToStemBranch(jd)
+		case "lunar":
+			r.Lunar = NewLunar(zcal.LunarDateFromJD(nil, jd))
+		case "weekday":
+			r.Weekday = time.Weekday(zcal.JDToWeekday(jd)).String()
+		}
+	}
+	return r
+}
+
+// Text returns the date of calendar c in r as text.
+func (r Result) Text(c string) string {
+	switch c {
+	case "western":
+		return r.Western
+	case "gonghe":
+		return r.Gonghe
+	case "ghc":
+		return r.GHC
+	case "jd":
+		if r.JD != nil {
+			return strconv.FormatFloat(*r.JD, 'f', -1, 64)
+		}
+	case "ganzhi":
+		return r.Ganzhi
+	case "lunar":
+		if r.Lunar != nil {
+			return r.Lunar.Text
+		}
+	case "weekday":
+		return r.Weekday
+	}
+	return ""
+}
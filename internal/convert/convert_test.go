@@ -0,0 +1,95 @@
+package convert_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tzengyuxio/zcal"
+	. "github.com/tzengyuxio/zcal/internal/convert"
+)
+
+func TestParseDate(t *testing.T) {
+	for _, pair := range []struct {
+		calendar, date string
+		jd             float64
+	}{
+		{"western", "2000-01-01", 2451544.5},
+		{"Western", "-841-02-12", zcal.JDOfGongheFirstDay},
+		{"gonghe", "1-1-1", zcal.JDOfGongheFirstDay},
+		{"ghc", "2841-11-28", 2451544.5},
+		{"jd", "2451545", 2451545},
+		{"jd", "-0.5", MinJD},
+		{"western", "-4713-01-01", MinJD},
+	} {
+		jd, err := ParseDate(pair.calendar, pair.date)
+		assert.NoError(t, err, "For %s %s", pair.calendar, pair.date)
+		assert.Equal(t, pair.jd, jd, "For %s %s", pair.calendar, pair.date)
+	}
+
+	for _, pair := range []struct {
+		calendar, date string
+		err            error
+	}{
+		{"western", "0-01-01", zcal.ErrYearZero},
+		{"western", "1582-10-10", zcal.ErrNonexistentDate},
+		{"gonghe", "2858-12-31", zcal.ErrInvalidDay},
+		{"ghc", "2858-1", zcal.ErrLayout},
+		{"jd", "NaN", ErrRange},
+		{"jd", "+Inf", ErrRange},
+		{"jd", "-1000000", ErrRange},
+		{"jd", "-0.6", ErrRange},
+		{"jd", "1e12", ErrRange},
+		{"western", "-4714-12-31", ErrRange},
+		{"western", "20000-01-01", ErrRange},
+		{"gonghe", "100000-1-1", ErrRange},
+	} {
+		_, err := ParseDate(pair.calendar, pair.date)
+		assert.True(t, errors.Is(err, pair.err), "For %s %s got %v", pair.calendar, pair.date, err)
+	}
+	for _, pair := range [][2]string{
+		{"western", "2000/01/01"},
+		{"western", "+2000-01-01"},
+		{"western", "2000-+1-01"},
+		{"jd", "x"},
+		{"mayan", "2000-01-01"},
+	} {
+		_, err := ParseDate(pair[0], pair[1])
+		assert.Error(t, err, "For %s %s", pair[0], pair[1])
+	}
+}
+
+func TestConvert(t *testing.T) {
+	calendars, err := ParseCalendars(" Gonghe,lunar,jd")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"gonghe", "lunar", "jd"}, calendars)
+	_, err = ParseCalendars("gonghe,,jd")
+	assert.Error(t, err)
+
+	r := Convert(2451544.5, Calendars)
+	assert.Equal(t, "2000-01-01", r.Western)
+	assert.Equal(t, "Saturday", r.Weekday)
+	assert.Equal(t, &Lunar{1999, 11, false, 25, "1999年十一月廿五"}, r.Lunar)
+	assert.Equal(t, "2451544.5", r.Text("jd"))
+	assert.Equal(t, "1999年十一月廿五", r.Text("lunar"))
+
+	r = Convert(2451544.5, []string{"ganzhi"})
+	assert.Equal(t, Result{Ganzhi: "戊午"}, r)
+	assert.Equal(t, "", r.Text("jd"))
+	assert.Equal(t, "", r.Text("lunar"))
+
+	// the lunar years are in the Western year numbering as the Western dates
+	r = Convert(zcal.JDOfGongheFirstDay, []string{"western", "lunar"})
+	assert.Equal(t, "-841-02-12", r.Western)
+	assert.Equal(t, &Lunar{-841, 1, false, 2, "-841年正月初二"}, r.Lunar)
+	for _, y := range []int{-841, -1, 1, 2017} {
+		assert.Equal(t, y, WesternYear(AstronomicalYear(y)))
+	}
+	assert.Equal(t, 0, AstronomicalYear(-1))
+
+	// out of the years of lunar calendar
+	r = Convert(1e8, []string{"lunar"})
+	assert.Nil(t, r.Lunar)
+	assert.Equal(t, "", r.Text("lunar"))
+	assert.Nil(t, NewLunar(zcal.LunarDate{}))
+}
@@ -0,0 +1,274 @@
+package zcal
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+
+	"github.com/soniakeys/unit"
+)
+
+// lunarTZ is the time zone of Chinese lunar calendar (農曆), UTC+8.
+const lunarTZ = 8.0
+
+// meanSynodicMonth is the mean length of a lunation in days, and jdeOfNewMoon0
+// is the mean new moon of lunation 0 (2000-01-06), see (49.1) p. 349.
+const (
+	meanSynodicMonth = 29.530588861
+	jdeOfNewMoon0    = 2451550.09766
+)
+
+var lunarMonthNames = []string{"正", "二", "三", "四", "五", "六", "七", "八", "九", "十", "十一", "十二"}
+var lunarDayNames = []string{"初", "十", "廿", "三"}
+var chineseDigits = []string{"〇", "一", "二", "三", "四", "五", "六", "七", "八", "九", "十"}
+
+// lunarMonth is a month in Chinese lunar calendar.
+type lunarMonth struct {
+	year, month int
+	leap        bool
+	jd          float64 // the first day
+	days        int
+}
+
+// suiKey is the key of suiCache, e is the ephemeris of a comparable type.
+type suiKey struct {
+	e Ephemeris
+	y int
+}
+
+// maxSuiCache bounds the number of the sui in suiCache, which is about the
+// centuries around now for a few ephemerides.
+const maxSuiCache = 2048
+
+var suiCache = struct {
+	sync.Mutex
+	m map[suiKey][]lunarMonth
+}{m: make(map[suiKey][]lunarMonth)}
+
+// newMoon returns the Julian date in UT of the new moon of lunation k.
+func newMoon(k int) float64 {
+	return float64(MoonPhaseTT(Lunation(k), NewMoon).UT())
+}
+
+// localDay returns the Julian day number of the local date of jd.
+func localDay(jd float64) int {
+	return int(math.Floor(jd + .5))
+}
+
+// lunarSui returns the months of the sui (歲) of year y, from the month 11
+// which contains 冬至 of year y-1 to the month before the month 11 of year y.
+// It returns nil out of the supported years, the sui of MaxYear+1 contains the
+// end of lunar year MaxYear.
+func lunarSui(e Ephemeris, y int) []lunarMonth {
+	if y < MinYear || y > MaxYear+1 {
+		return nil
+	}
+	e = ephemeris(e)
+	// the ephemerides of non-comparable types, e.g. funcs, are not cached
+	cached := reflect.TypeOf(e).Comparable()
+	key := suiKey{e, y}
+	if cached {
+		suiCache.Lock()
+		months, ok := suiCache.m[key]
+		suiCache.Unlock()
+		if ok {
+			return months
+		}
+	}
+
+	offset := lunarTZ / 24
+	// month 11 is the month contains 冬至
+	month11 := func(y int) (int, float64) {
+		s, m := CalcDongzhiAndShuo(e, y, lunarTZ)
+		k := int(math.Floor((m-offset-jdeOfNewMoon0)/meanSynodicMonth + .5))
+		for localDay(newMoon(k)+offset) > localDay(s) {
+			k--
+		}
+		for localDay(newMoon(k+1)+offset) <= localDay(s) {
+			k++
+		}
+		return k, s
+	}
+	k1, s1 := month11(y - 1)
+	k2, _ := month11(y)
+
+	starts := make([]int, k2-k1+1)
+	for i := range starts {
+		starts[i] = localDay(newMoon(k1+i) + offset)
+	}
+
+	leap := -1
+	if k2-k1 == 13 {
+		// 無中置閏: the first month without zhongqi is the leap month
+		z := s1 - offset
+		for i := 1; i < 13; i++ {
+			z = solveSolarLongitude(e, unit.AngleFromDeg(math.Mod(270+30*float64(i), 360)), z+30.4)
+			if localDay(float64(TT(z).UT().Local(lunarTZ))) >= starts[i+1] {
+				leap = i
+				break
+			}
+		}
+	}
+
+	months := make([]lunarMonth, 0, k2-k1)
+	year, month := y-1, 10
+	for i := 0; i < k2-k1; i++ {
+		if i != leap {
+			month++
+			if month > 12 {
+				year, month = year+1, 1
+			}
+		}
+		months = append(months, lunarMonth{
+			year:  year,
+			month: month,
+			leap:  i == leap,
+			jd:    float64(starts[i]) - .5,
+			days:  starts[i+1] - starts[i],
+		})
+	}
+
+	if cached {
+		suiCache.Lock()
+		if len(suiCache.m) >= maxSuiCache {
+			// evict an arbitrary sui
+			for k := range suiCache.m {
+				delete(suiCache.m, k)
+				break
+			}
+		}
+		suiCache.m[key] = months
+		suiCache.Unlock()
+	}
+	return months
+}
+
+// lunarYear returns the months of Chinese lunar year y, from 正月 to 十二月.
+func lunarYear(e Ephemeris, y int) []lunarMonth {
+	var months []lunarMonth
+	for _, s := range [][]lunarMonth{lunarSui(e, y), lunarSui(e, y+1)} {
+		for _, m := range s {
+			if m.year == y {
+				months = append(months, m)
+			}
+		}
+	}
+	return months
+}
+
+// JDToChineseLunar converts Julian date to Chinese lunar calendar (農曆) date.
+//
+// The modern rules are used: the day begins at midnight of UTC+8, the month
+// contains 冬至 is the 11th month, and in a sui (歲, from one 11th month to the
+// next) of 13 months, the first month without zhongqi (中氣) is the leap
+// month. Year y begins at 正月 and is in astronomical year numbering. A nil e
+// means the default ephemeris. Zeros are returned for the dates out of the
+// years MinYear to MaxYear, see JDToChineseLunarChecked.
+func JDToChineseLunar(e Ephemeris, jd float64) (year, month int, isLeap bool, day int) {
+	year, month, isLeap, day, _ = JDToChineseLunarChecked(e, jd)
+	return
+}
+
+// JDToChineseLunarChecked is like JDToChineseLunar but returns an error
+// wrapping ErrYearRange for the dates out of the years MinYear to MaxYear.
+func JDToChineseLunarChecked(e Ephemeris, jd float64) (year, month int, isLeap bool, day int, err error) {
+	// the comparisons are false for NaN
+	if jd > GregorianCalendarToJD(MinYear-1, 1, 1) && jd < GregorianCalendarToJD(MaxYear+2, 1, 1) {
+		d := localDay(jd)
+		// the Gregorian year, which may be a year off near the new year, and
+		// 冬至 drifts away from December in the remote years
+		y := 2000 + int(math.Floor((jd-2451544.5)/365.2425))
+		for _, sy := range []int{y, y + 1, y - 1} {
+			for _, m := range lunarSui(e, sy) {
+				if start := localDay(m.jd); d >= start && d < start+m.days && checkYear(m.year) == nil {
+					return m.year, m.month, m.leap, d - start + 1, nil
+				}
+			}
+		}
+	}
+	return 0, 0, false, 0, fmt.Errorf("zcal: Julian date %v: %w", jd, ErrYearRange)
+}
+
+// ChineseLunarToJD converts Chinese lunar calendar date to Julian date, see
+// JDToChineseLunar for the rules. An error is returned if the month or the
+// day does not exist in the year, or the year is out of MinYear to MaxYear.
+func ChineseLunarToJD(e Ephemeris, year, month int, isLeap bool, day int) (float64, error) {
+	if err := checkYear(year); err != nil {
+		return 0, err
+	}
+	for _, m := range lunarYear(e, year) {
+		if m.month == month && m.leap == isLeap {
+			if day < 1 || day > m.days {
+				return 0, &DateError{"Lunar", year, month, day, ErrInvalidDay}
+			}
+			return m.jd + float64(day-1), nil
+		}
+	}
+	return 0, &DateError{"Lunar", year, month, day, ErrInvalidMonth}
+}
+
+// ChineseLunarLeapMonth returns the leap month of Chinese lunar year y, or 0
+// if there is no leap month in the year.
+func ChineseLunarLeapMonth(e Ephemeris, year int) int {
+	for _, m := range lunarYear(e, year) {
+		if m.leap {
+			return m.month
+		}
+	}
+	return 0
+}
+
+// ChineseLunarMonthDays returns the number of days (29 or 30) of the month,
+// or 0 if the month does not exist in the year.
+func ChineseLunarMonthDays(e Ephemeris, year, month int, isLeap bool) int {
+	for _, m := range lunarYear(e, year) {
+		if m.month == month && m.leap == isLeap {
+			return m.days
+		}
+	}
+	return 0
+}
+
+// LunarDate is a date in Chinese lunar calendar, see JDToChineseLunar.
+type LunarDate struct {
+	Year, Month int
+	IsLeap      bool
+	Day         int
+}
+
+// LunarDateFromJD returns the Chinese lunar calendar date of the given Julian
+// date. A nil e means the default ephemeris.
+func LunarDateFromJD(e Ephemeris, jd float64) LunarDate {
+	y, m, leap, d := JDToChineseLunar(e, jd)
+	return LunarDate{y, m, leap, d}
+}
+
+// String returns the date in Chinese, e.g. 2017年閏六月初一.
+func (l LunarDate) String() string {
+	return fmt.Sprintf("%d年%s%s", l.Year, LunarMonthName(l.Month, l.IsLeap), LunarDayName(l.Day))
+}
+
+// LunarMonthName returns the Chinese name of the lunar month, e.g. 正月, 閏六月
+// and 十二月.
+func LunarMonthName(month int, isLeap bool) string {
+	s := lunarMonthNames[(month+11)%12] + "月"
+	if isLeap {
+		s = "閏" + s
+	}
+	return s
+}
+
+// LunarDayName returns the Chinese name of the lunar day, e.g. 初一, 十五, 廿三
+// and 三十.
+func LunarDayName(day int) string {
+	switch day {
+	case 10:
+		return "初十"
+	case 20:
+		return "二十"
+	case 30:
+		return "三十"
+	}
+	return lunarDayNames[day/10] + chineseDigits[day%10]
+}
@@ -0,0 +1,164 @@
+package zcal_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/soniakeys/unit"
+	"github.com/stretchr/testify/assert"
+	. "github.com/tzengyuxio/zcal"
+)
+
+func TestChineseLunarNewYear(t *testing.T) {
+	e := DefaultEphemeris()
+
+	for _, pair := range []struct {
+		year, m, d int
+		leap       int
+	}{
+		{1984, 2, 2, 10},
+		{2006, 1, 29, 7},
+		{2012, 1, 23, 4},
+		{2017, 1, 28, 6},
+		{2018, 2, 16, 0},
+		{2019, 2, 5, 0},
+		{2020, 1, 25, 4},
+		{2023, 1, 22, 2},
+		{2025, 1, 29, 6},
+		{2026, 2, 17, 0},
+		{2033, 1, 31, 11}, // 2033 年閏十一月
+		{2034, 2, 19, 0},
+	} {
+		jd, err := ChineseLunarToJD(e, pair.year, 1, false, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, GregorianCalendarToJD(pair.year, pair.m, pair.d), jd, "For lunar year %d", pair.year)
+		assert.Equal(t, pair.leap, ChineseLunarLeapMonth(e, pair.year), "For lunar year %d", pair.year)
+	}
+}
+
+func TestJDToChineseLunar(t *testing.T) {
+	e := DefaultEphemeris()
+
+	for _, pair := range []struct {
+		y, m, d int
+		ly, lm  int
+		leap    bool
+		ld      int
+	}{
+		{2017, 1, 27, 2016, 12, false, 30}, // 除夕
+		{2017, 1, 28, 2017, 1, false, 1},
+		{2017, 7, 22, 2017, 6, false, 29},
+		{2017, 7, 23, 2017, 6, true, 1},
+		{2017, 8, 21, 2017, 6, true, 30},
+		{2017, 8, 22, 2017, 7, false, 1},
+		{2017, 10, 4, 2017, 8, false, 15}, // 中秋
+		{2033, 12, 22, 2033, 11, true, 1},
+		{2000, 1, 1, 1999, 11, false, 25},
+	} {
+		jd := GregorianCalendarToJD(pair.y, pair.m, pair.d)
+		ly, lm, leap, ld := JDToChineseLunar(e, jd)
+		assert.Equal(t, []int{pair.ly, pair.lm, pair.ld}, []int{ly, lm, ld}, "For date %04d-%02d-%02d", pair.y, pair.m, pair.d)
+		assert.Equal(t, pair.leap, leap, "For date %04d-%02d-%02d", pair.y, pair.m, pair.d)
+
+		back, err := ChineseLunarToJD(e, ly, lm, leap, ld)
+		assert.NoError(t, err)
+		assert.Equal(t, jd, back)
+	}
+}
+
+func TestChineseLunarRoundTrip(t *testing.T) {
+	e := DefaultEphemeris()
+
+	start := GregorianCalendarToJD(1990, 1, 1)
+	_, prevMonth, _, prevDay := JDToChineseLunar(e, start-1)
+	for jd := start; jd < GregorianCalendarToJD(2040, 1, 1); jd++ {
+		y, m, leap, d := JDToChineseLunar(e, jd)
+		back, err := ChineseLunarToJD(e, y, m, leap, d)
+		assert.NoError(t, err)
+		assert.Equal(t, jd, back)
+		if d == 1 {
+			assert.Contains(t, []int{29, 30}, prevDay, "For lunar %d-%d", y, prevMonth)
+		} else {
+			assert.Equal(t, prevDay+1, d)
+		}
+		prevMonth, prevDay = m, d
+	}
+}
+
+func TestChineseLunarToJDError(t *testing.T) {
+	e := DefaultEphemeris()
+
+	_, err := ChineseLunarToJD(e, 2017, 5, true, 1)
+	assert.True(t, errors.Is(err, ErrInvalidMonth))
+	_, err = ChineseLunarToJD(e, 2017, 13, false, 1)
+	assert.True(t, errors.Is(err, ErrInvalidMonth))
+	_, err = ChineseLunarToJD(e, 2017, 1, false, 31)
+	assert.True(t, errors.Is(err, ErrInvalidDay))
+	assert.Equal(t, 30, ChineseLunarMonthDays(e, 2017, 6, true))
+	assert.Equal(t, 0, ChineseLunarMonthDays(e, 2017, 5, true))
+
+	// out of the supported years
+	_, err = ChineseLunarToJD(e, MaxYear+1, 1, false, 1)
+	assert.True(t, errors.Is(err, ErrYearRange))
+	assert.Equal(t, LunarDate{}, LunarDateFromJD(e, 1e9))
+	assert.Equal(t, LunarDate{}, LunarDateFromJD(e, -1e9))
+	for _, jd := range []float64{math.NaN(), math.Inf(1), 1e12, -1e9} {
+		_, _, _, _, err = JDToChineseLunarChecked(e, jd)
+		assert.True(t, errors.Is(err, ErrYearRange), "For %v", jd)
+	}
+
+	// the first and the last days of the supported years
+	for _, year := range []int{MinYear, MaxYear} {
+		assert.NotEqual(t, 12, ChineseLunarLeapMonth(e, year))
+		first, err := ChineseLunarToJD(e, year, 1, false, 1)
+		assert.NoError(t, err)
+		last, err := ChineseLunarToJD(e, year, 12, false, ChineseLunarMonthDays(e, year, 12, false))
+		assert.NoError(t, err)
+		for _, jd := range []float64{first, last} {
+			y, m, leap, d, err := JDToChineseLunarChecked(e, jd)
+			assert.NoError(t, err)
+			back, err := ChineseLunarToJD(e, y, m, leap, d)
+			assert.NoError(t, err)
+			assert.Equal(t, jd, back, "For %d", year)
+		}
+		if year == MinYear {
+			_, _, _, _, err = JDToChineseLunarChecked(e, first-1)
+		} else {
+			_, _, _, _, err = JDToChineseLunarChecked(e, last+1)
+		}
+		assert.True(t, errors.Is(err, ErrYearRange), "For %d", year)
+	}
+}
+
+// funcEphemeris is an ephemeris of a non-comparable type.
+type funcEphemeris func(jde float64) (L, B unit.Angle, R float64)
+
+func (f funcEphemeris) Position(jde float64) (L, B unit.Angle, R float64) {
+	return f(jde)
+}
+
+func TestLunarEphemeris(t *testing.T) {
+	e := funcEphemeris(DefaultEphemeris().Position)
+	jd := GregorianCalendarToJD(2017, 7, 23)
+	assert.Equal(t, LunarDate{2017, 6, true, 1}, LunarDateFromJD(e, jd))
+	assert.Equal(t, LunarDateFromJD(nil, jd+1000), LunarDateFromJD(e, jd+1000))
+
+	// more years than the cache holds
+	for y := 1000; y < 4000; y++ {
+		assert.NotZero(t, ChineseLunarMonthDays(nil, y, 1, false))
+	}
+}
+
+func TestLunarNames(t *testing.T) {
+	assert.Equal(t, "正月", LunarMonthName(1, false))
+	assert.Equal(t, "閏六月", LunarMonthName(6, true))
+	assert.Equal(t, "十一月", LunarMonthName(11, false))
+	assert.Equal(t, "十二月", LunarMonthName(12, false))
+	for day, name := range map[int]string{
+		1: "初一", 9: "初九", 10: "初十", 11: "十一", 15: "十五",
+		19: "十九", 20: "二十", 21: "廿一", 29: "廿九", 30: "三十",
+	} {
+		assert.Equal(t, name, LunarDayName(day))
+	}
+}
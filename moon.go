@@ -0,0 +1,115 @@
+package zcal
+
+import (
+	"math"
+
+	mp "github.com/soniakeys/meeus/moonphase"
+)
+
+// MoonPhase is one of the four principal phases of the moon.
+type MoonPhase int
+
+// The phases in the order of a lunation.
+const (
+	NewMoon      MoonPhase = iota // 朔
+	FirstQuarter                  // 上弦
+	FullMoon                      // 望
+	LastQuarter                   // 下弦
+)
+
+var moonPhaseNames = []string{"朔", "上弦", "望", "下弦"}
+
+func (p MoonPhase) String() string {
+	return moonPhaseNames[p&3]
+}
+
+// brownLunation0 is the Brown lunation number of lunation 0, lunation 1 of
+// Brown began at the new moon of 1923-01-17.
+const brownLunation0 = 953
+
+// Lunation is a lunation number of Meeus, lunation 0 begins at the new moon
+// of 2000-01-06, and the negative numbers are before it.
+type Lunation int
+
+// Brown returns the Brown lunation number.
+func (k Lunation) Brown() int {
+	return int(k) + brownLunation0
+}
+
+// LunationOf returns the lunation which contains the Julian date jd in UT,
+// i.e. the last new moon at or before jd begins the lunation.
+func LunationOf(jd float64) Lunation {
+	k := Lunation(math.Floor((jd - jdeOfNewMoon0) / meanSynodicMonth))
+	for float64(MoonPhaseTT(k+1, NewMoon).UT()) <= jd {
+		k++
+	}
+	for float64(MoonPhaseTT(k, NewMoon).UT()) > jd {
+		k--
+	}
+	return k
+}
+
+// MoonPhaseTT returns the instant in Terrestrial Time of the phase in
+// lunation k, computed by the algorithm of Meeus (chapter 49).
+func MoonPhaseTT(k Lunation, phase MoonPhase) TT {
+	q := float64(phase&3) / 4
+	y := 2000 + (float64(k)+q)/12.3685
+	switch phase & 3 {
+	case FirstQuarter:
+		return TT(mp.First(y))
+	case FullMoon:
+		return TT(mp.Full(y))
+	case LastQuarter:
+		return TT(mp.Last(y))
+	}
+	return TT(mp.New(y))
+}
+
+// MoonPhaseJD returns the instant of the phase in lunation k, in the local
+// time of tz hours offset as SolarTermJD.
+func MoonPhaseJD(k Lunation, phase MoonPhase, tz float64) float64 {
+	return float64(MoonPhaseTT(k, phase).UT().Local(tz))
+}
+
+// MoonPhaseInstant is the moment of a phase of the moon.
+type MoonPhaseInstant struct {
+	Phase    MoonPhase
+	Lunation Lunation
+	JD       float64 // local time, i.e. UT plus time zone offset
+}
+
+// Date returns the local Western calendar date of the phase.
+func (m MoonPhaseInstant) Date() (year, month, day int) {
+	return DefaultWesternCalendar.FromJD(m.JD)
+}
+
+// GongheDate returns the local Gonghe calendar date of the phase.
+func (m MoonPhaseInstant) GongheDate() GongheDate {
+	return GongheDateFromJD(m.JD)
+}
+
+// LunarDate returns the Chinese lunar calendar date of the local date of the
+// phase. A nil e means the default ephemeris.
+func (m MoonPhaseInstant) LunarDate(e Ephemeris) LunarDate {
+	return LunarDateFromJD(e, m.JD)
+}
+
+// MoonPhases returns the instants of the phases in the local time of tz hours
+// offset, from the local Julian date from to before to, in time order. For
+// example, the phases of a month are
+//
+//	MoonPhases(GregorianCalendarToJD(2017, 7, 1), GregorianCalendarToJD(2017, 8, 1), 8)
+func MoonPhases(from, to float64, tz float64) []MoonPhaseInstant {
+	var phases []MoonPhaseInstant
+	for k := LunationOf(from-tz/24) - 1; ; k++ {
+		for p := NewMoon; p <= LastQuarter; p++ {
+			jd := MoonPhaseJD(k, p, tz)
+			if jd >= to {
+				return phases
+			}
+			if jd >= from {
+				phases = append(phases, MoonPhaseInstant{p, k, jd})
+			}
+		}
+	}
+}
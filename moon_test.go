@@ -0,0 +1,76 @@
+package zcal_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/tzengyuxio/zcal"
+)
+
+func TestMoonPhase(t *testing.T) {
+	assert.Equal(t, "朔", NewMoon.String())
+	assert.Equal(t, "上弦", FirstQuarter.String())
+	assert.Equal(t, "望", FullMoon.String())
+	assert.Equal(t, "下弦", LastQuarter.String())
+
+	// example 49.a, p. 353: new moon 1977-02-18 3:37:42 TD
+	assert.InDelta(t, 2443192.65118, float64(MoonPhaseTT(-283, NewMoon)), 1e-5)
+	// example 49.b, p. 353: the first last quarter of 2044, 2044-01-21 23:48:17 TD
+	assert.InDelta(t, 2467636.49186, float64(MoonPhaseTT(544, LastQuarter)), 1e-5)
+}
+
+func TestLunation(t *testing.T) {
+	for _, pair := range []struct {
+		jd       float64
+		lunation Lunation
+		brown    int
+	}{
+		{GregorianCalendarToJD(2000, 1, 7), 0, 953}, // 朔 2000-01-06 18:14 UT
+		{GregorianCalendarToJD(2000, 1, 6), -1, 952},
+		{GregorianCalendarToJD(1923, 1, 18), -952, 1},
+		{GregorianCalendarToJD(1977, 2, 18), -284, 669},
+		{GregorianCalendarToJD(1977, 2, 19), -283, 670},
+		{GregorianCalendarToJD(2017, 7, 24), 217, 1170},
+	} {
+		assert.Equal(t, pair.lunation, LunationOf(pair.jd), "For %.1f", pair.jd)
+		assert.Equal(t, pair.brown, pair.lunation.Brown())
+	}
+}
+
+func TestMoonPhases(t *testing.T) {
+	// 2017 年 7 月月相，東八區
+	phases := MoonPhases(GregorianCalendarToJD(2017, 7, 1), GregorianCalendarToJD(2017, 8, 1), 8)
+	assert.Len(t, phases, 5)
+	for i, pair := range []struct {
+		phase    MoonPhase
+		lunation Lunation
+		date     [3]int
+		lunar    LunarDate
+	}{
+		{FirstQuarter, 216, [3]int{2017, 7, 1}, LunarDate{2017, 6, false, 8}},
+		{FullMoon, 216, [3]int{2017, 7, 9}, LunarDate{2017, 6, false, 16}},
+		{LastQuarter, 216, [3]int{2017, 7, 17}, LunarDate{2017, 6, false, 24}},
+		{NewMoon, 217, [3]int{2017, 7, 23}, LunarDate{2017, 6, true, 1}},
+		{FirstQuarter, 217, [3]int{2017, 7, 30}, LunarDate{2017, 6, true, 8}},
+	} {
+		p := phases[i]
+		assert.Equal(t, pair.phase, p.Phase)
+		assert.Equal(t, pair.lunation, p.Lunation)
+		y, m, d := p.Date()
+		assert.Equal(t, pair.date, [3]int{y, m, d}, "For %s", p.Phase)
+		assert.Equal(t, pair.lunar, p.LunarDate(nil), "For %s", p.Phase)
+	}
+	// 朔 2017-07-23 17:46 (UTC+8)
+	assert.InDelta(t, GregorianCalendarToJD(2017, 7, 23)+(17+46.0/60)/24, phases[3].JD, 1.0/1440)
+	assert.Equal(t, NewGongheDate(2858, 6, 21), phases[3].GongheDate())
+	assert.Equal(t, phases[3].JD, MoonPhaseJD(217, NewMoon, 8))
+
+	// 49 phases in 2017, 13 full moons
+	phases = MoonPhases(GregorianCalendarToJD(2017, 1, 1), GregorianCalendarToJD(2018, 1, 1), 8)
+	assert.Len(t, phases, 49)
+	for i := 1; i < len(phases); i++ {
+		assert.Equal(t, (phases[i-1].Phase+1)%4, phases[i].Phase)
+		assert.True(t, phases[i].JD > phases[i-1].JD)
+	}
+	assert.Empty(t, MoonPhases(2457936, 2457936, 8))
+}
@@ -0,0 +1,138 @@
+package zcal
+
+import (
+	"math"
+
+	"github.com/soniakeys/meeus/coord"
+	"github.com/soniakeys/meeus/moonillum"
+	"github.com/soniakeys/meeus/moonposition"
+	"github.com/soniakeys/meeus/nutation"
+	"github.com/soniakeys/unit"
+)
+
+// kmPerAU is the astronomical unit in kilometers.
+const kmPerAU = 149597870.7
+
+// ApparentMoonEquatorial returns the apparent geocentric equatorial position
+// of the moon, and the distance in kilometers, see chapter 47 of Meeus.
+func ApparentMoonEquatorial(jde float64) (α unit.RA, δ unit.Angle, Δ float64) {
+	λ, β, Δ := moonposition.Position(jde)
+	Δψ, Δε := nutation.Nutation(jde)
+	ε := nutation.MeanObliquity(jde) + Δε
+	sε, cε := ε.Sincos()
+	α, δ = coord.EclToEq(λ+Δψ, β, sε, cε)
+	return
+}
+
+// moonEquatorial returns the position of the moon in degrees at jd in UT, see
+// ApparentMoonEquatorial.
+func moonEquatorial(jd float64) (α, δ, Δ float64) {
+	ra, dec, Δ := ApparentMoonEquatorial(float64(JD(jd).TT()))
+	return ra.Deg(), dec.Deg(), Δ
+}
+
+// moonRiseAltitude returns the altitude of the center of the moon in degrees
+// at the moonrise and moonset at distance Δ in kilometers, which corrects the
+// parallax, the semidiameter and the refraction, see (15.1) p. 102.
+func moonRiseAltitude(Δ float64) float64 {
+	return .7275*moonposition.Parallax(Δ).Deg() - 34.0/60
+}
+
+// MoonAltitude returns the geometric altitude of the center of the moon in
+// degrees at the local Julian date jd, i.e. geocentric and without the
+// refraction.
+func (o Observer) MoonAltitude(jd float64) float64 {
+	return o.altitude(moonPosition, float64(LocalJD(jd).UT(o.TZ)))
+}
+
+// moonPosition is the equatorialFunc of the moon.
+func moonPosition(jd float64) (float64, float64) {
+	α, δ, _ := moonEquatorial(jd)
+	return α, δ
+}
+
+// MoonTimes are the moonrise, the upper transit and the moonset of a local
+// date at an observer, in local Julian dates. An event is NaN if it does not
+// occur on the date, since the moon rises about 50 minutes later every day,
+// and it is always above or below the horizon for days at high latitudes.
+type MoonTimes struct {
+	Rise, Transit, Set float64
+}
+
+// Moon returns the moonrise, transit and moonset on the local date of jd, the
+// moonrise and moonset are corrected by the dip of the horizon at the
+// elevation.
+func (o Observer) Moon(jd float64) MoonTimes {
+	day := o.localMidnight(jd)
+	dip := horizonDip(o.Elevation)
+	// above returns the altitude above the horizon of the moonrise
+	above := func(t float64) float64 {
+		_, _, Δ := moonEquatorial(t)
+		return o.altitude(moonPosition, t) - moonRiseAltitude(Δ) + dip
+	}
+	hourAngle := func(t float64) float64 {
+		α, _, _ := moonEquatorial(t)
+		return o.hourAngle(t, α)
+	}
+
+	m := MoonTimes{math.NaN(), math.NaN(), math.NaN()}
+	local := func(t float64) float64 { return float64(JD(t).Local(o.TZ)) }
+	const step = 1.0 / 24
+	t0, h0, H0 := day, above(day), hourAngle(day)
+	for i := 1; i <= 24; i++ {
+		t1 := day + float64(i)*step
+		h1, H1 := above(t1), hourAngle(t1)
+		if h0 < 0 && h1 >= 0 && math.IsNaN(m.Rise) {
+			m.Rise = local(bisect(above, t0, t1))
+		}
+		if h0 >= 0 && h1 < 0 && math.IsNaN(m.Set) {
+			m.Set = local(bisect(above, t1, t0))
+		}
+		// the hour angle increases through 0, but not wraps at 180
+		if H0 < 0 && H1 >= 0 && H1-H0 < 180 && math.IsNaN(m.Transit) {
+			m.Transit = local(bisect(hourAngle, t0, t1))
+		}
+		t0, h0, H0 = t1, h1, H1
+	}
+	return m
+}
+
+// bisect returns the root of f between lo and hi, where f(lo) < 0 <= f(hi).
+func bisect(f func(float64) float64, lo, hi float64) float64 {
+	for math.Abs(hi-lo) > 1e-6 {
+		mid := (lo + hi) / 2
+		if f(mid) < 0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// Illumination is the illumination of the moon at an instant.
+type Illumination struct {
+	Fraction   float64 // the illuminated fraction of the disk, from 0 to 1
+	PhaseAngle float64 // degrees, 0 at full moon and 180 at new moon
+	Waxing     bool    // true from new moon to full moon
+	Lunation   Lunation
+	Age        float64 // days since the new moon of the lunation
+}
+
+// MoonIllumination returns the illumination of the moon at the local Julian
+// date jd in time zone tz hours, see chapter 48 of Meeus. A nil e means the
+// default ephemeris.
+func MoonIllumination(e Ephemeris, jd, tz float64) Illumination {
+	ut := float64(LocalJD(jd).UT(tz))
+	α, δ, Δ := moonEquatorial(ut)
+	α0, δ0, R := ApparentSunEquatorial(ephemeris(e), float64(JD(ut).TT()))
+	i := moonillum.PhaseAngleEq(unit.RAFromDeg(α), unit.AngleFromDeg(δ), Δ, α0, δ0, R*kmPerAU)
+	k := LunationOf(ut)
+	return Illumination{
+		Fraction:   (1 + i.Cos()) / 2,
+		PhaseAngle: i.Deg(),
+		Waxing:     math.Mod(α-α0.Deg()+720, 360) < 180,
+		Lunation:   k,
+		Age:        ut - float64(MoonPhaseTT(k, NewMoon).UT()),
+	}
+}
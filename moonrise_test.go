@@ -0,0 +1,76 @@
+package zcal_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/tzengyuxio/zcal"
+)
+
+func TestObserverMoon(t *testing.T) {
+	taipei := Observer{Latitude: 25.0330, Longitude: 121.5654, TZ: 8}
+
+	for _, pair := range []struct {
+		date               [3]int
+		rise, transit, set float64
+	}{
+		// 朔 2017-07-23 17:46, the moon rises and sets with the sun
+		{[3]int{2017, 7, 23}, clock(2017, 7, 23, 4, 51), clock(2017, 7, 23, 11, 44), clock(2017, 7, 23, 18, 34)},
+		{[3]int{2017, 7, 20}, clock(2017, 7, 20, 1, 54), clock(2017, 7, 20, 8, 40), clock(2017, 7, 20, 15, 30)},
+	} {
+		m := taipei.Moon(GregorianCalendarToJD(pair.date[0], pair.date[1], pair.date[2]))
+		assert.InDelta(t, pair.rise, m.Rise, 1.0/1440, "For %v", pair.date)
+		assert.InDelta(t, pair.transit, m.Transit, 1.0/1440, "For %v", pair.date)
+		assert.InDelta(t, pair.set, m.Set, 1.0/1440, "For %v", pair.date)
+		// the center is above the horizon by the parallax at moonrise
+		assert.InDelta(t, .16, taipei.MoonAltitude(m.Rise), .02)
+		h := taipei.MoonAltitude(m.Transit)
+		assert.True(t, h > taipei.MoonAltitude(m.Transit-.01) && h > taipei.MoonAltitude(m.Transit+.01))
+	}
+
+	// no moonset on 2017-07-31, the moon sets after midnight
+	m := taipei.Moon(GregorianCalendarToJD(2017, 7, 31))
+	assert.False(t, math.IsNaN(m.Rise))
+	assert.True(t, math.IsNaN(m.Set))
+	m = taipei.Moon(GregorianCalendarToJD(2017, 8, 1))
+	assert.InDelta(t, clock(2017, 8, 1, 0, 6), m.Set, 5.0/1440)
+
+	// the moon is always above the horizon of Tromsø on 2017-12-04 from the
+	// rise of the previous day, and transits after the midnight
+	m = Observer{Latitude: 69.6492, Longitude: 18.9553, TZ: 1}.Moon(GregorianCalendarToJD(2017, 12, 4))
+	assert.True(t, math.IsNaN(m.Transit))
+}
+
+func TestMoonIllumination(t *testing.T) {
+	// example 48.a, p. 347: 1992-04-12 0h TD, k = 0.6786
+	il := MoonIllumination(nil, 2448724.5, 0)
+	assert.InDelta(t, .6786, il.Fraction, .0005)
+	assert.InDelta(t, 69.0756, il.PhaseAngle, .02)
+	assert.True(t, il.Waxing)
+
+	for _, pair := range []struct {
+		jd       float64
+		fraction float64
+		waxing   bool
+		lunation Lunation
+		age      float64
+	}{
+		// 朔 2017-07-23 17:46 (UTC+8)
+		{clock(2017, 7, 23, 17, 46), 0, true, 217, 0},
+		{clock(2017, 7, 23, 12, 0), .001, false, 216, 29.06},
+		// 上弦 2017-07-30 23:23
+		{clock(2017, 7, 30, 23, 23), .5, true, 217, 7.23},
+		// 望 2017-08-08 02:11
+		{clock(2017, 8, 8, 2, 11), 1, true, 217, 15.35},
+	} {
+		il := MoonIllumination(nil, pair.jd, 8)
+		assert.InDelta(t, pair.fraction, il.Fraction, .01, "For %.3f", pair.jd)
+		assert.InDelta(t, (1+math.Cos(il.PhaseAngle*math.Pi/180))/2, il.Fraction, 1e-9)
+		assert.Equal(t, pair.lunation, il.Lunation, "For %.3f", pair.jd)
+		assert.InDelta(t, pair.age, il.Age, .01, "For %.3f", pair.jd)
+		if pair.fraction > .01 && pair.fraction < .99 {
+			assert.Equal(t, pair.waxing, il.Waxing, "For %.3f", pair.jd)
+		}
+	}
+}
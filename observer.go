@@ -0,0 +1,218 @@
+package zcal
+
+import (
+	"errors"
+	"math"
+
+	"github.com/soniakeys/meeus/sidereal"
+)
+
+// Observer is a location on the Earth.
+type Observer struct {
+	Latitude  float64 // degrees, north is positive
+	Longitude float64 // degrees, east is positive
+	Elevation float64 // meters above sea level
+	TZ        float64 // hours offset of the local time, e.g. 8 for UTC+8
+}
+
+// The altitudes of the center of the sun in degrees at the events, the
+// sunrise and sunset are corrected by the atmospheric refraction of 34' and
+// the semidiameter of 16'.
+const (
+	SunriseAltitude              = -50.0 / 60
+	CivilTwilightAltitude        = -6.0
+	NauticalTwilightAltitude     = -12.0
+	AstronomicalTwilightAltitude = -18.0
+)
+
+// Errors of the rising and setting, the body does not cross the altitude on
+// the date.
+var (
+	ErrAlwaysAbove = errors.New("zcal: always above the altitude")
+	ErrAlwaysBelow = errors.New("zcal: always below the altitude")
+)
+
+// siderealRate is the ratio of a sidereal day to a solar day.
+const siderealRate = 1.00273790935
+
+// equatorialFunc returns the apparent right ascension and declination in
+// degrees of a body at the Julian date in UT.
+type equatorialFunc func(jd float64) (α, δ float64)
+
+// sunEquatorial returns the equatorialFunc of the sun.
+func sunEquatorial(e Ephemeris) equatorialFunc {
+	e = ephemeris(e)
+	return func(jd float64) (float64, float64) {
+		α, δ, _ := ApparentSunEquatorial(e, float64(JD(jd).TT()))
+		return α.Deg(), δ.Deg()
+	}
+}
+
+// localMidnight returns the UT of the beginning of the local date of jd.
+func (o Observer) localMidnight(jd float64) float64 {
+	return float64(LocalJD(math.Floor(jd+.5) - .5).UT(o.TZ))
+}
+
+// hourAngle returns the local hour angle in degrees of right ascension α at
+// jd in UT, in (-180, 180].
+func (o Observer) hourAngle(jd, α float64) float64 {
+	h := math.Mod(sidereal.Apparent(jd).Hour()*15+o.Longitude-α, 360)
+	if h > 180 {
+		h -= 360
+	} else if h <= -180 {
+		h += 360
+	}
+	return h
+}
+
+// altitude returns the geometric altitude in degrees of the body at jd in UT.
+func (o Observer) altitude(pos equatorialFunc, jd float64) float64 {
+	α, δ := pos(jd)
+	φ, δr := o.Latitude*math.Pi/180, δ*math.Pi/180
+	H := o.hourAngle(jd, α) * math.Pi / 180
+	return math.Asin(math.Sin(φ)*math.Sin(δr)+math.Cos(φ)*math.Cos(δr)*math.Cos(H)) * 180 / math.Pi
+}
+
+// transit returns the UT of the upper transit of the body on the local date
+// of jd.
+func (o Observer) transit(pos equatorialFunc, jd float64) float64 {
+	day := o.localMidnight(jd)
+	// start from the local mean noon, which is on the date even in the zones
+	// far from the meridian of the offset, e.g. UTC+13
+	t := day + .5 + o.TZ/24 - o.Longitude/360
+	t = o.transitNear(pos, t-math.Floor(t-day))
+	// the transit of the previous or the next day
+	if t < day {
+		t = o.transitNear(pos, t+1)
+	} else if t >= day+1 {
+		t = o.transitNear(pos, t-1)
+	}
+	return t
+}
+
+// transitNear returns the UT of the upper transit of the body nearest to the
+// UT t.
+func (o Observer) transitNear(pos equatorialFunc, t float64) float64 {
+	for i := 0; i < 10; i++ {
+		α, _ := pos(t)
+		c := o.hourAngle(t, α) / 360 / siderealRate
+		t -= c
+		if math.Abs(c) < 1e-7 {
+			break
+		}
+	}
+	return t
+}
+
+// riseSet returns the UT of the rising and setting of the body at altitude h0
+// in degrees around transit t, see chapter 15 of Meeus.
+func (o Observer) riseSet(pos equatorialFunc, t, h0 float64) (rise, set float64, err error) {
+	φ := o.Latitude * math.Pi / 180
+	_, δ := pos(t)
+	δr := δ * math.Pi / 180
+	cosH0 := (math.Sin(h0*math.Pi/180) - math.Sin(φ)*math.Sin(δr)) / (math.Cos(φ) * math.Cos(δr))
+	if cosH0 < -1 {
+		return 0, 0, ErrAlwaysAbove
+	}
+	if cosH0 > 1 {
+		return 0, 0, ErrAlwaysBelow
+	}
+	H0 := math.Acos(cosH0) * 180 / math.Pi
+
+	solve := func(t float64) float64 {
+		for i := 0; i < 10; i++ {
+			α, δ := pos(t)
+			H := o.hourAngle(t, α) * math.Pi / 180
+			h := o.altitude(pos, t)
+			c := (h - h0) / (360 * math.Cos(δ*math.Pi/180) * math.Cos(φ) * math.Sin(H))
+			t += c
+			if math.Abs(c) < 1e-7 {
+				break
+			}
+		}
+		return t
+	}
+	return solve(t - H0/360/siderealRate), solve(t + H0/360/siderealRate), nil
+}
+
+// SunAltitude returns the geometric altitude of the center of the sun in
+// degrees at the local Julian date jd, i.e. without the refraction. A nil e
+// means the default ephemeris.
+func (o Observer) SunAltitude(e Ephemeris, jd float64) float64 {
+	return o.altitude(sunEquatorial(e), float64(LocalJD(jd).UT(o.TZ)))
+}
+
+// SolarNoon returns the local time of the transit of the sun on the local
+// date of jd. A nil e means the default ephemeris.
+func (o Observer) SolarNoon(e Ephemeris, jd float64) float64 {
+	return float64(JD(o.transit(sunEquatorial(e), jd)).Local(o.TZ))
+}
+
+// SunRiseSet returns the local times when the center of the sun crosses the
+// altitude in degrees around the solar noon of the local date of jd, e.g.
+// CivilTwilightAltitude for the civil dawn and dusk. ErrAlwaysAbove or
+// ErrAlwaysBelow is returned if the sun does not cross the altitude, e.g. in
+// polar day or night. A nil e means the default ephemeris.
+func (o Observer) SunRiseSet(e Ephemeris, jd, altitude float64) (rise, set float64, err error) {
+	pos := sunEquatorial(e)
+	rise, set, err = o.riseSet(pos, o.transit(pos, jd), altitude)
+	if err != nil {
+		return 0, 0, err
+	}
+	return float64(JD(rise).Local(o.TZ)), float64(JD(set).Local(o.TZ)), nil
+}
+
+// horizonDip returns the dip of the horizon in degrees at the elevation in
+// meters.
+func horizonDip(elevation float64) float64 {
+	if elevation <= 0 {
+		return 0
+	}
+	return 0.0353 * math.Sqrt(elevation)
+}
+
+// SunTimes are the events of the sun of a local date at an observer, in local
+// Julian dates. An event is NaN if the sun does not cross its altitude on the
+// date, e.g. the sunrise and sunset in polar day or night.
+type SunTimes struct {
+	Noon                               float64
+	Sunrise, Sunset                    float64
+	CivilDawn, CivilDusk               float64
+	NauticalDawn, NauticalDusk         float64
+	AstronomicalDawn, AstronomicalDusk float64
+	// DayLength is the hours from sunrise to sunset, 24 in polar day and 0
+	// in polar night.
+	DayLength float64
+}
+
+// Sun returns the events of the sun on the local date of jd, the sunrise and
+// sunset are corrected by the dip of the horizon at the elevation. A nil e
+// means the default ephemeris.
+func (o Observer) Sun(e Ephemeris, jd float64) SunTimes {
+	pos := sunEquatorial(e)
+	t := o.transit(pos, jd)
+	s := SunTimes{Noon: float64(JD(t).Local(o.TZ))}
+	for _, ev := range []struct {
+		altitude  float64
+		rise, set *float64
+	}{
+		{SunriseAltitude - horizonDip(o.Elevation), &s.Sunrise, &s.Sunset},
+		{CivilTwilightAltitude, &s.CivilDawn, &s.CivilDusk},
+		{NauticalTwilightAltitude, &s.NauticalDawn, &s.NauticalDusk},
+		{AstronomicalTwilightAltitude, &s.AstronomicalDawn, &s.AstronomicalDusk},
+	} {
+		rise, set, err := o.riseSet(pos, t, ev.altitude)
+		if err != nil {
+			*ev.rise, *ev.set = math.NaN(), math.NaN()
+			if ev.rise == &s.Sunrise && err == ErrAlwaysAbove {
+				s.DayLength = 24
+			}
+			continue
+		}
+		*ev.rise, *ev.set = float64(JD(rise).Local(o.TZ)), float64(JD(set).Local(o.TZ))
+		if ev.rise == &s.Sunrise {
+			s.DayLength = (set - rise) * 24
+		}
+	}
+	return s
+}
@@ -0,0 +1,104 @@
+package zcal_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/tzengyuxio/zcal"
+)
+
+// clock returns the local Julian date of the time of the date.
+func clock(y, m, d, hour, min int) float64 {
+	return GregorianCalendarToJD(y, m, d) + (float64(hour)+float64(min)/60)/24
+}
+
+func TestObserverSun(t *testing.T) {
+	taipei := Observer{Latitude: 25.0330, Longitude: 121.5654, TZ: 8}
+	london := Observer{Latitude: 51.4769, Longitude: 0, TZ: 1}
+	newYork := Observer{Latitude: 40.7128, Longitude: -74.0060, TZ: -4}
+	sydney := Observer{Latitude: -33.8688, Longitude: 151.2093, TZ: 10}
+
+	for _, pair := range []struct {
+		o                     Observer
+		date                  [3]int
+		sunrise, noon, sunset float64
+	}{
+		{taipei, [3]int{2017, 6, 21}, clock(2017, 6, 21, 5, 5), clock(2017, 6, 21, 11, 55), clock(2017, 6, 21, 18, 46)},
+		{taipei, [3]int{2017, 12, 22}, clock(2017, 12, 22, 6, 35), clock(2017, 12, 22, 11, 52), clock(2017, 12, 22, 17, 10)},
+		{london, [3]int{2017, 6, 21}, clock(2017, 6, 21, 4, 43), clock(2017, 6, 21, 13, 2), clock(2017, 6, 21, 21, 21)},
+		{newYork, [3]int{2017, 6, 21}, clock(2017, 6, 21, 5, 25), clock(2017, 6, 21, 12, 58), clock(2017, 6, 21, 20, 31)},
+		{sydney, [3]int{2017, 6, 21}, clock(2017, 6, 21, 7, 0), clock(2017, 6, 21, 11, 57), clock(2017, 6, 21, 16, 54)},
+	} {
+		o := pair.o
+		jd := GregorianCalendarToJD(pair.date[0], pair.date[1], pair.date[2])
+		s := o.Sun(nil, jd)
+		assert.InDelta(t, pair.sunrise, s.Sunrise, 1.0/1440, "For %v", pair.date)
+		assert.InDelta(t, pair.noon, s.Noon, 1.0/1440, "For %v", pair.date)
+		assert.InDelta(t, pair.sunset, s.Sunset, 1.0/1440, "For %v", pair.date)
+		assert.InDelta(t, (s.Sunset-s.Sunrise)*24, s.DayLength, 1e-9)
+		assert.Equal(t, s.Noon, o.SolarNoon(nil, jd+.3))
+
+		// the twilights are in order, and the sun is at the altitudes
+		assert.True(t, s.CivilDawn < s.Sunrise && s.NauticalDawn < s.CivilDawn)
+		assert.True(t, s.Sunset < s.CivilDusk && s.CivilDusk < s.NauticalDusk)
+		assert.InDelta(t, SunriseAltitude, o.SunAltitude(nil, s.Sunrise), 1e-5)
+		assert.InDelta(t, CivilTwilightAltitude, o.SunAltitude(nil, s.CivilDusk), 1e-5)
+		rise, set, err := o.SunRiseSet(nil, jd, AstronomicalTwilightAltitude)
+		if err == nil {
+			assert.Equal(t, s.AstronomicalDawn, rise)
+			assert.Equal(t, s.AstronomicalDusk, set)
+		}
+	}
+
+	// the horizon of 玉山 is lower
+	yushan := taipei
+	yushan.Elevation = 3952
+	s, s0 := yushan.Sun(nil, GregorianCalendarToJD(2017, 6, 21)), taipei.Sun(nil, GregorianCalendarToJD(2017, 6, 21))
+	assert.InDelta(t, 11.0/1440, s0.Sunrise-s.Sunrise, 1.0/1440)
+	assert.Equal(t, s0.CivilDawn, s.CivilDawn)
+}
+
+func TestObserverPolar(t *testing.T) {
+	tromso := Observer{Latitude: 69.6492, Longitude: 18.9553, TZ: 1}
+
+	// midnight sun
+	s := tromso.Sun(nil, GregorianCalendarToJD(2017, 6, 21))
+	assert.Equal(t, 24.0, s.DayLength)
+	assert.True(t, math.IsNaN(s.Sunrise) && math.IsNaN(s.Sunset) && math.IsNaN(s.CivilDusk))
+	_, _, err := tromso.SunRiseSet(nil, GregorianCalendarToJD(2017, 6, 21), SunriseAltitude)
+	assert.Equal(t, ErrAlwaysAbove, err)
+
+	// polar night, but civil twilight at noon
+	s = tromso.Sun(nil, GregorianCalendarToJD(2017, 12, 21))
+	assert.Equal(t, 0.0, s.DayLength)
+	assert.True(t, math.IsNaN(s.Sunrise))
+	assert.InDelta(t, clock(2017, 12, 21, 9, 31), s.CivilDawn, 2.0/1440)
+	assert.InDelta(t, clock(2017, 12, 21, 11, 42), s.Noon, 1.0/1440)
+	_, _, err = tromso.SunRiseSet(nil, GregorianCalendarToJD(2017, 12, 21), SunriseAltitude)
+	assert.Equal(t, ErrAlwaysBelow, err)
+}
+
+func TestObserverFarZone(t *testing.T) {
+	// the local mean noon is far from 12 p.m. in the zones east of the date
+	// line
+	wellington := Observer{Latitude: -41.29, Longitude: 174.78, TZ: 13}
+	kiritimati := Observer{Latitude: 1.87, Longitude: -157.43, TZ: 14}
+
+	for _, pair := range []struct {
+		o    Observer
+		date [3]int
+		noon float64
+	}{
+		{wellington, [3]int{2017, 1, 1}, clock(2017, 1, 1, 13, 24)},
+		{wellington, [3]int{2017, 6, 21}, clock(2017, 6, 21, 13, 23)},
+		{kiritimati, [3]int{2017, 1, 1}, clock(2017, 1, 1, 12, 33)},
+	} {
+		jd := GregorianCalendarToJD(pair.date[0], pair.date[1], pair.date[2])
+		noon := pair.o.SolarNoon(nil, jd)
+		assert.InDelta(t, pair.noon, noon, 1.0/1440, "For %v", pair.date)
+		s := pair.o.Sun(nil, jd)
+		assert.Equal(t, noon, s.Noon)
+		assert.True(t, s.Sunrise < s.Noon && s.Noon < s.Sunset)
+	}
+}
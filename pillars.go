@@ -0,0 +1,67 @@
+package zcal
+
+import "math"
+
+// ZiHour is the convention of the hour 子 between 23:00 and 24:00.
+type ZiHour int
+
+const (
+	// ZiHourNextDay means the day begins at 23:00 (子初換日), the day pillar
+	// of 23:00-24:00 is the one of the next day.
+	ZiHourNextDay ZiHour = iota
+	// ZiHourLate means 23:00-24:00 is the late 子 hour (晚子時) of the same
+	// day, and 00:00-01:00 is the early 子 hour (早子時). The day pillar of
+	// the late 子 hour is the one of the same day, but the hour pillar is still
+	// derived from the stem of the next day.
+	ZiHourLate
+)
+
+// Pillars is the Four Pillars (四柱八字) of a moment, each of them is a
+// stem-branch.
+type Pillars struct {
+	Year, Month, Day, Hour string
+}
+
+func (p Pillars) String() string {
+	return p.Year + " " + p.Month + " " + p.Day + " " + p.Hour
+}
+
+// FourPillars returns the year, month, day and hour pillars of the moment jd,
+// in local time of time zone tz (hours).
+//
+// The year begins at 立春 and the months begin at the sectional terms (節),
+// e.g. the month 寅 is from 立春 to 驚蟄. The hour stem is derived from the
+// day stem (五鼠遁), the hour 子 between 23:00 and 24:00 follows zi.
+func FourPillars(e Ephemeris, jd, tz float64, zi ZiHour) Pillars {
+	local := jd + tz/24
+
+	y, _, _, _ := JDToGregorianCalendar(local)
+	if local < SolarTermJD(e, y, LiChun, tz) {
+		y--
+	}
+	n := 0 // months after 寅
+	for i := 22; i > 0; i -= 2 {
+		if local >= SolarTermJD(e, y, SolarTerm(i), tz) {
+			n = i / 2
+			break
+		}
+	}
+
+	day := int(math.Floor(local+.5)) - 11 // see JDToGanzhi
+	hour := (local + .5 - math.Floor(local+.5)) * 24
+	h := int(math.Floor((hour+1)/2)) % 12
+	hourDay := day
+	if hour >= 23 {
+		hourDay++
+		if zi == ZiHourNextDay {
+			day++
+		}
+	}
+
+	return Pillars{
+		Year:  StemBranch(y - 4),
+		Month: StemBranch((y-4)*12 + n + 2),
+		Day:   StemBranch(day),
+		Hour:  StemBranch(hourDay*12 + h),
+	}
+}
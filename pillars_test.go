@@ -0,0 +1,37 @@
+package zcal_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/tzengyuxio/zcal"
+)
+
+// utc8 returns the UT Julian date of the local time in UTC+8.
+func utc8(y, m, d int, hour, min float64) float64 {
+	return GregorianCalendarToJD(y, m, d) + (hour+min/60-8)/24
+}
+
+func TestFourPillars(t *testing.T) {
+	e := DefaultEphemeris()
+
+	for _, pair := range []struct {
+		jd      float64
+		zi      ZiHour
+		pillars string
+	}{
+		{utc8(2000, 1, 1, 12, 0), ZiHourNextDay, "己卯 丙子 戊午 戊午"},
+		{utc8(2000, 1, 6, 12, 0), ZiHourNextDay, "己卯 丁丑 癸亥 戊午"},  // 小寒 2000-01-06 09:01
+		{utc8(2017, 2, 3, 23, 30), ZiHourNextDay, "丙申 辛丑 壬戌 庚子"}, // 立春 2017-02-03 23:34
+		{utc8(2017, 2, 3, 23, 40), ZiHourNextDay, "丁酉 壬寅 壬戌 庚子"},
+		{utc8(2017, 2, 3, 23, 40), ZiHourLate, "丁酉 壬寅 辛酉 庚子"},
+		{utc8(2017, 2, 4, 0, 30), ZiHourLate, "丁酉 壬寅 壬戌 庚子"},
+		{utc8(2017, 2, 4, 1, 0), ZiHourLate, "丁酉 壬寅 壬戌 辛丑"},
+		{utc8(2017, 2, 3, 22, 59), ZiHourNextDay, "丙申 辛丑 辛酉 己亥"},
+		{utc8(1984, 2, 4, 12, 0), ZiHourNextDay, "癸亥 乙丑 戊辰 戊午"}, // 立春 1984-02-04 23:19
+		{utc8(1984, 2, 5, 12, 0), ZiHourNextDay, "甲子 丙寅 己巳 庚午"},
+	} {
+		p := FourPillars(e, pair.jd, 8, pair.zi)
+		assert.Equal(t, pair.pillars, p.String(), "For JD %f", pair.jd)
+	}
+}
@@ -0,0 +1,400 @@
+package zcal
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// CalendarSystem is a calendar in which a recurrence rule is expressed.
+type CalendarSystem int
+
+// The calendar systems of recurrence rules.
+const (
+	GongheSystem  CalendarSystem = iota // Gonghe calendar, 4/100/500 leap rule
+	GHCSystem                           // Gonghe calendar with 4/128 leap rule
+	LunarSystem                         // Chinese lunar calendar
+	WesternSystem                       // DefaultWesternCalendar
+)
+
+// Frequency is the FREQ of a recurrence rule.
+type Frequency int
+
+// The frequencies of recurrence rules.
+const (
+	Yearly Frequency = iota
+	Monthly
+	Daily
+)
+
+// Skip is the behavior when a recurrence falls on a nonexistent date, e.g.
+// the 31st day of an odd Gonghe month, the 30th day of a short lunar month, or
+// a lunar leap month in a year without it, see RFC 7529.
+type Skip int
+
+const (
+	// SkipOmit omits the recurrence.
+	SkipOmit Skip = iota
+	// SkipBackward moves the recurrence to the last day of the month, or to
+	// the common month of the missing leap month.
+	SkipBackward
+	// SkipForward moves the recurrence to the first day of the next month, or
+	// to the month after the missing leap month.
+	SkipForward
+)
+
+// Rule is a recurrence rule like RRULE of RFC 5545, in the terms of a
+// calendar system.
+type Rule struct {
+	Calendar CalendarSystem
+	Freq     Frequency
+	Interval int // 0 means 1
+	// ByMonth are the months, and ByLeapMonth are the leap months of Chinese
+	// lunar calendar (e.g. "5L" of RFC 7529). For yearly rules the month of
+	// the start is used if both are empty.
+	ByMonth, ByLeapMonth []int
+	// ByMonthDay are the days of month, the negative ones count from the last
+	// day. For yearly and monthly rules the day of the start is used if it is
+	// empty.
+	ByMonthDay []int
+	Count      int     // the number of occurrences, 0 means unlimited
+	Until      float64 // the last Julian date of occurrences, 0 means unlimited
+	Skip       Skip
+	// Ephemeris of Chinese lunar calendar, nil means the default one.
+	Ephemeris Ephemeris
+}
+
+// ErrUnbounded means all the occurrences of a rule without Count and Until
+// are requested.
+var ErrUnbounded = errors.New("zcal: recurrence without Count or Until")
+
+// maxEmptyPeriods stops a rule which matches no date, e.g. the 31st day of
+// Gonghe month 1 without SkipBackward.
+const maxEmptyPeriods = 1000
+
+// recurMonth is a month of a calendar system.
+type recurMonth struct {
+	year, month int
+	leap        bool
+	jd          float64 // the first day
+	days        int
+}
+
+// months returns the months of year y in the calendar system.
+func (r Rule) months(y int) []recurMonth {
+	var months []recurMonth
+	switch r.Calendar {
+	case GongheSystem, GHCSystem:
+		leap := LeapYearGonghe(y)
+		if r.Calendar == GHCSystem {
+			leap = LeapYearGHC(y + 1)
+		}
+		for m := 1; m <= 12; m++ {
+			jd := GongheCalendarToJD(y, m, 1)
+			if r.Calendar == GHCSystem {
+				jd = GHCToJD(y, m, 1)
+			}
+			months = append(months, recurMonth{y, m, false, jd, daysInGongheMonth(m, leap)})
+		}
+	case LunarSystem:
+		for _, m := range lunarYear(r.Ephemeris, y) {
+			months = append(months, recurMonth{m.year, m.month, m.leap, m.jd, m.days})
+		}
+	case WesternSystem:
+		w := DefaultWesternCalendar
+		if y == 0 {
+			return nil
+		}
+		next := w.ToJD(y+1, 1, 1)
+		if y == -1 {
+			next = w.ToJD(1, 1, 1)
+		}
+		for m := 12; m >= 1; m-- {
+			jd := w.ToJD(y, m, 1)
+			months = append([]recurMonth{{y, m, false, jd, int(next - jd)}}, months...)
+			next = jd
+		}
+	}
+	return months
+}
+
+// monthOf returns the month which contains jd.
+func (r Rule) monthOf(jd float64) recurMonth {
+	var y int
+	switch r.Calendar {
+	case GongheSystem:
+		y, _, _, _ = JDToGongheCalendar(jd)
+	case GHCSystem:
+		y, _, _, _ = JDToGHC(jd)
+	case LunarSystem:
+		y, _, _, _ = JDToChineseLunar(r.Ephemeris, jd)
+	case WesternSystem:
+		y, _, _ = DefaultWesternCalendar.FromJD(jd)
+	}
+	for _, m := range r.months(y) {
+		if jd >= m.jd && jd < m.jd+float64(m.days) {
+			return m
+		}
+	}
+	return recurMonth{}
+}
+
+// nextYear returns the year after y, which skips year 0 of Western calendar.
+func (r Rule) nextYear(y, n int) int {
+	if r.Calendar == WesternSystem {
+		a := astronomicalYear(y) + n
+		if a <= 0 {
+			a--
+		}
+		return a
+	}
+	return y + n
+}
+
+// Recurrence iterates the occurrences of a rule, see Rule.Iterator.
+type Recurrence struct {
+	rule     Rule
+	start    float64
+	first    recurMonth
+	months   []recurMonth // the year of the month cursor of monthly rules
+	month    int          // the index of the month cursor in months
+	period   int          // the next period to expand
+	pending  []float64    // the occurrences of the expanded periods
+	emitted  int
+	previous float64
+	done     bool
+}
+
+// Iterator returns the iterator of the occurrences of r from the date of
+// start (Julian date of the day). The start itself is an occurrence only if
+// it matches the rule.
+func (r Rule) Iterator(start float64) *Recurrence {
+	if r.Interval <= 0 {
+		r.Interval = 1
+	}
+	first := r.monthOf(start)
+	if len(r.ByMonth) == 0 && len(r.ByLeapMonth) == 0 && r.Freq == Yearly {
+		if first.leap {
+			r.ByLeapMonth = []int{first.month}
+		} else {
+			r.ByMonth = []int{first.month}
+		}
+	}
+	if len(r.ByMonthDay) == 0 && r.Freq != Daily {
+		r.ByMonthDay = []int{int(start-first.jd) + 1}
+	}
+	return &Recurrence{rule: r, start: start, first: first, previous: start - 1}
+}
+
+// Next returns the Julian date of the next occurrence, ok is false if there is
+// no more.
+func (it *Recurrence) Next() (jd float64, ok bool) {
+	r := it.rule
+	for empty := 0; len(it.pending) == 0; empty++ {
+		if it.done || empty >= maxEmptyPeriods {
+			it.done = true
+			return 0, false
+		}
+		it.pending = it.expand(it.period)
+		it.period++
+	}
+	jd, it.pending = it.pending[0], it.pending[1:]
+	if (r.Count > 0 && it.emitted >= r.Count) || (r.Until != 0 && jd > r.Until) {
+		it.done = true
+		return 0, false
+	}
+	it.emitted++
+	it.previous = jd
+	return jd, true
+}
+
+// NextTime returns the midnight in loc of the next occurrence.
+func (it *Recurrence) NextTime(loc *time.Location) (time.Time, bool) {
+	jd, ok := it.Next()
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := dateToTime(jd, loc)
+	if err != nil {
+		it.done = true
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// expand returns the occurrences of the period n after the start, which are
+// after the previous occurrence.
+func (it *Recurrence) expand(n int) []float64 {
+	r := it.rule
+	var jds []float64
+	switch r.Freq {
+	case Yearly:
+		y := r.nextYear(it.first.year, n*r.Interval)
+		months := r.months(y)
+		for _, m := range months {
+			if r.matchMonth(m) {
+				jds = append(jds, r.days(m)...)
+			}
+		}
+		if r.Calendar == LunarSystem && r.Skip != SkipOmit {
+			jds = append(jds, r.missingLeapMonths(months)...)
+		}
+	case Monthly:
+		if !it.advance(n) {
+			it.done = true
+			return nil
+		}
+		if m := it.months[it.month]; r.matchMonth(m) {
+			jds = r.days(m)
+		}
+	case Daily:
+		// a period of Daily rules is 28 days to reduce the empty periods
+		for k := 0; k < 28; k++ {
+			jd := it.start + float64((n*28+k)*r.Interval)
+			m := r.monthOf(jd)
+			if !r.matchMonth(m) {
+				continue
+			}
+			if len(r.ByMonthDay) == 0 {
+				jds = append(jds, jd)
+				continue
+			}
+			for _, d := range r.days(m) {
+				if d == jd {
+					jds = append(jds, jd)
+				}
+			}
+		}
+	}
+
+	sort.Float64s(jds)
+	var result []float64
+	for _, jd := range jds {
+		if jd >= it.start && jd > it.previous && (len(result) == 0 || jd > result[len(result)-1]) {
+			result = append(result, jd)
+		}
+	}
+	return result
+}
+
+// advance moves the month cursor of a monthly rule to the period n, the
+// periods are expanded in order. It returns false if the month is out of the
+// supported years.
+func (it *Recurrence) advance(n int) bool {
+	r := it.rule
+	k := r.Interval
+	if n == 0 {
+		it.months, it.month, k = r.months(it.first.year), 0, 0
+		for it.month < len(it.months) && (it.months[it.month].month != it.first.month || it.months[it.month].leap != it.first.leap) {
+			it.month++
+		}
+	}
+	// the leap months are counted
+	for ; k > 0 && it.month < len(it.months); k-- {
+		if it.month++; it.month >= len(it.months) {
+			it.months, it.month = r.months(r.nextYear(it.months[0].year, 1)), 0
+		}
+	}
+	return it.month < len(it.months)
+}
+
+func (r Rule) matchMonth(m recurMonth) bool {
+	if len(r.ByMonth) == 0 && len(r.ByLeapMonth) == 0 {
+		return true
+	}
+	if m.leap {
+		return containsInt(r.ByLeapMonth, m.month)
+	}
+	return containsInt(r.ByMonth, m.month)
+}
+
+// missingLeapMonths returns the occurrences of the leap months in ByLeapMonth
+// which are absent in the year, moved by Skip.
+func (r Rule) missingLeapMonths(months []recurMonth) []float64 {
+	var jds []float64
+	for _, lm := range r.ByLeapMonth {
+		found := false
+		for _, m := range months {
+			found = found || (m.leap && m.month == lm)
+		}
+		if found {
+			continue
+		}
+		target := lm
+		if r.Skip == SkipForward {
+			target = lm%12 + 1
+		}
+		for _, m := range months {
+			if !m.leap && m.month == target {
+				jds = append(jds, r.days(m)...)
+			}
+		}
+	}
+	return jds
+}
+
+// days returns the Julian dates of ByMonthDay in month m.
+func (r Rule) days(m recurMonth) []float64 {
+	var jds []float64
+	for _, d := range r.ByMonthDay {
+		if d < 0 {
+			d += m.days + 1
+			if d < 1 {
+				continue
+			}
+		}
+		if d > m.days {
+			switch r.Skip {
+			case SkipBackward:
+				d = m.days
+			case SkipForward:
+				d = m.days + 1
+			default:
+				continue
+			}
+		}
+		jds = append(jds, m.jd+float64(d-1))
+	}
+	return jds
+}
+
+func containsInt(a []int, n int) bool {
+	for _, x := range a {
+		if x == n {
+			return true
+		}
+	}
+	return false
+}
+
+// Between returns the occurrences of r from start in [from, to].
+func (r Rule) Between(start, from, to float64) []float64 {
+	var jds []float64
+	it := r.Iterator(start)
+	for {
+		jd, ok := it.Next()
+		if !ok || jd > to {
+			return jds
+		}
+		if jd >= from {
+			jds = append(jds, jd)
+		}
+	}
+}
+
+// All returns all the occurrences of r from start, r must have Count or
+// Until.
+func (r Rule) All(start float64) ([]float64, error) {
+	if r.Count <= 0 && r.Until == 0 {
+		return nil, ErrUnbounded
+	}
+	var jds []float64
+	it := r.Iterator(start)
+	for {
+		jd, ok := it.Next()
+		if !ok {
+			return jds, nil
+		}
+		jds = append(jds, jd)
+	}
+}
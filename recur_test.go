@@ -0,0 +1,159 @@
+package zcal_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/tzengyuxio/zcal"
+)
+
+func gongheDates(jds []float64) []GongheDate {
+	var dates []GongheDate
+	for _, jd := range jds {
+		dates = append(dates, GongheDateFromJD(jd))
+	}
+	return dates
+}
+
+func westernDates(jds []float64) [][3]int {
+	var dates [][3]int
+	for _, jd := range jds {
+		y, m, d := DefaultWesternCalendar.FromJD(jd)
+		dates = append(dates, [3]int{y, m, d})
+	}
+	return dates
+}
+
+func TestRecurGonghe(t *testing.T) {
+	start := NewGongheDate(2858, 11, 1).JD()
+
+	// every Gonghe month on day 15
+	jds, err := Rule{Calendar: GongheSystem, Freq: Monthly, ByMonthDay: []int{15}, Count: 4}.All(start)
+	assert.NoError(t, err)
+	assert.Equal(t, []GongheDate{
+		NewGongheDate(2858, 11, 15), NewGongheDate(2858, 12, 15),
+		NewGongheDate(2859, 1, 15), NewGongheDate(2859, 2, 15),
+	}, gongheDates(jds))
+
+	// the 31st day exists in the even months only, and 12-31 in leap years
+	jds, _ = Rule{Calendar: GongheSystem, Freq: Monthly, ByMonthDay: []int{31}, Count: 3}.All(start)
+	assert.Equal(t, []GongheDate{
+		NewGongheDate(2859, 2, 31), NewGongheDate(2859, 4, 31), NewGongheDate(2859, 6, 31),
+	}, gongheDates(jds))
+	jds, _ = Rule{Calendar: GongheSystem, Freq: Monthly, ByMonthDay: []int{31}, Count: 3, Skip: SkipBackward}.All(start)
+	assert.Equal(t, []GongheDate{
+		NewGongheDate(2858, 11, 30), NewGongheDate(2858, 12, 30), NewGongheDate(2859, 1, 30),
+	}, gongheDates(jds))
+	jds, _ = Rule{Calendar: GongheSystem, Freq: Monthly, ByMonthDay: []int{31}, Count: 2, Skip: SkipForward}.All(start)
+	assert.Equal(t, []GongheDate{NewGongheDate(2858, 12, 1), NewGongheDate(2859, 1, 1)}, gongheDates(jds))
+	jds, _ = Rule{Calendar: GongheSystem, Freq: Monthly, ByMonthDay: []int{-1}, Count: 3}.All(start)
+	assert.Equal(t, []GongheDate{
+		NewGongheDate(2858, 11, 30), NewGongheDate(2858, 12, 30), NewGongheDate(2859, 1, 30),
+	}, gongheDates(jds))
+
+	// leap days 12-31
+	jds, _ = Rule{Calendar: GongheSystem, ByMonth: []int{12}, ByMonthDay: []int{31}, Count: 3}.All(start)
+	for _, g := range gongheDates(jds) {
+		assert.True(t, g.IsLeapYear())
+	}
+	assert.Equal(t, NewGongheDate(2860, 12, 31), GongheDateFromJD(jds[0]))
+	jds, _ = Rule{Calendar: GHCSystem, ByMonth: []int{12}, ByMonthDay: []int{31}, Count: 3}.All(start)
+	for _, jd := range jds {
+		g := GHCDateFromJD(jd)
+		assert.True(t, g.IsLeapYear())
+		assert.Equal(t, [2]int{12, 31}, [2]int{g.Month, g.Day})
+	}
+
+	// yearly with interval and until
+	jds, _ = Rule{Calendar: GongheSystem, Interval: 2, Until: NewGongheDate(2864, 1, 1).JD()}.All(start)
+	assert.Equal(t, []GongheDate{
+		NewGongheDate(2858, 11, 1), NewGongheDate(2860, 11, 1), NewGongheDate(2862, 11, 1),
+	}, gongheDates(jds))
+}
+
+func TestRecurLunar(t *testing.T) {
+	e := DefaultEphemeris()
+	start := GregorianCalendarToJD(2017, 1, 1)
+
+	// every lunar 8/15 (中秋)
+	jds, err := Rule{Calendar: LunarSystem, Ephemeris: e, ByMonth: []int{8}, ByMonthDay: []int{15}, Count: 3}.All(start)
+	assert.NoError(t, err)
+	assert.Equal(t, [][3]int{{2017, 10, 4}, {2018, 9, 24}, {2019, 9, 13}}, westernDates(jds))
+
+	// the leap month is counted by monthly rules, 2017 閏六月
+	l17, _ := ChineseLunarToJD(e, 2017, 6, false, 1)
+	jds, _ = Rule{Calendar: LunarSystem, Freq: Monthly, Count: 3}.All(l17)
+	for i, want := range []LunarDate{{2017, 6, false, 1}, {2017, 6, true, 1}, {2017, 7, false, 1}} {
+		assert.Equal(t, want, LunarDateFromJD(e, jds[i]))
+	}
+
+	// 閏六月初一 in the years with and without 閏六月
+	leap, _ := ChineseLunarToJD(e, 2017, 6, true, 1)
+	jds, _ = Rule{Calendar: LunarSystem, Until: GregorianCalendarToJD(2030, 1, 1)}.All(leap)
+	assert.Equal(t, [][3]int{{2017, 7, 23}, {2025, 7, 25}}, westernDates(jds))
+	jds, _ = Rule{Calendar: LunarSystem, Count: 3, Skip: SkipBackward}.All(leap)
+	assert.Equal(t, []LunarDate{{2017, 6, true, 1}, {2018, 6, false, 1}, {2019, 6, false, 1}}, []LunarDate{
+		LunarDateFromJD(e, jds[0]), LunarDateFromJD(e, jds[1]), LunarDateFromJD(e, jds[2])})
+	jds, _ = Rule{Calendar: LunarSystem, Count: 2, Skip: SkipForward}.All(leap)
+	assert.Equal(t, LunarDate{2018, 7, false, 1}, LunarDateFromJD(e, jds[1]))
+
+	// 除夕, the last day of 十二月, 2017-01-01 is in lunar year 2016
+	jds, _ = Rule{Calendar: LunarSystem, ByMonth: []int{12}, ByMonthDay: []int{-1}, Count: 2}.All(start)
+	assert.Equal(t, [][3]int{{2017, 1, 27}, {2018, 2, 15}}, westernDates(jds))
+
+	// stops at the end of the supported years
+	last, _ := ChineseLunarToJD(e, MaxYear, 11, false, 1)
+	it := Rule{Calendar: LunarSystem, Freq: Monthly}.Iterator(last)
+	n := 0
+	for _, ok := it.Next(); ok; _, ok = it.Next() {
+		n++
+	}
+	assert.True(t, n > 2 && n < 20, "%d months", n)
+}
+
+func TestRecurWestern(t *testing.T) {
+	// Feb 29 in Western calendar, 1700 is a leap year in Julian calendar only
+	start := DefaultWesternCalendar.ToJD(1696, 2, 29)
+	jds, _ := Rule{Calendar: WesternSystem, Count: 3}.All(start)
+	assert.Equal(t, [][3]int{{1696, 2, 29}, {1704, 2, 29}, {1708, 2, 29}}, westernDates(jds))
+
+	// across the reform month of 21 days
+	start = DefaultWesternCalendar.ToJD(1582, 9, 30)
+	jds, _ = Rule{Calendar: WesternSystem, Freq: Monthly, ByMonthDay: []int{-1}, Count: 3}.All(start)
+	assert.Equal(t, [][3]int{{1582, 9, 30}, {1582, 10, 31}, {1582, 11, 30}}, westernDates(jds))
+
+	// no year 0
+	start = DefaultWesternCalendar.ToJD(-2, 3, 1)
+	jds, _ = Rule{Calendar: WesternSystem, Count: 3}.All(start)
+	assert.Equal(t, [][3]int{{-2, 3, 1}, {-1, 3, 1}, {1, 3, 1}}, westernDates(jds))
+
+	// daily with BYMONTH
+	start = GregorianCalendarToJD(2017, 1, 30)
+	jds, _ = Rule{Calendar: WesternSystem, Freq: Daily, Interval: 2, ByMonth: []int{2}, Count: 3}.All(start)
+	assert.Equal(t, [][3]int{{2017, 2, 1}, {2017, 2, 3}, {2017, 2, 5}}, westernDates(jds))
+}
+
+func TestRecurIterator(t *testing.T) {
+	start := GregorianCalendarToJD(2017, 1, 1)
+	r := Rule{Calendar: GongheSystem, Freq: Monthly, ByMonthDay: []int{1}}
+	_, err := r.All(start)
+	assert.Equal(t, ErrUnbounded, err)
+
+	jds := r.Between(start, GregorianCalendarToJD(2017, 6, 1), GregorianCalendarToJD(2017, 9, 1))
+	assert.Len(t, jds, 3)
+	for _, g := range gongheDates(jds) {
+		assert.Equal(t, 1, g.Day)
+	}
+
+	it := r.Iterator(start)
+	tm, ok := it.NextTime(time.UTC)
+	assert.True(t, ok)
+	y, m, d := WesternCalendarToGongheCalendar(tm.Year(), int(tm.Month()), tm.Day())
+	assert.Equal(t, NewGongheDate(y, m, d).Day, 1)
+	assert.Equal(t, 0, tm.Hour())
+
+	// never matches
+	_, ok = Rule{Calendar: GongheSystem, ByMonth: []int{1}, ByMonthDay: []int{31}}.Iterator(start).Next()
+	assert.False(t, ok)
+}
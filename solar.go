@@ -0,0 +1,207 @@
+package zcal
+
+import (
+	"fmt"
+	"math"
+)
+
+// LeapRule decides the leap years of a SolarCalendar.
+type LeapRule interface {
+	// IsLeap returns true if year y is a leap year.
+	IsLeap(y int) bool
+	// Cycle returns the number of years after which the rule repeats.
+	Cycle() int
+}
+
+// DivisorRule is a leap rule of nested divisors, e.g. {4, 100, 400} of
+// Gregorian calendar: a year divisible by 4 is leap, except divisible by 100,
+// except divisible by 400. Each divisor must be a multiple of the previous
+// one.
+type DivisorRule []int
+
+// IsLeap returns true if year y is a leap year.
+func (r DivisorRule) IsLeap(y int) bool {
+	leap := false
+	for i, d := range r {
+		if y%d == 0 {
+			leap = i%2 == 0
+		}
+	}
+	return leap
+}
+
+// Cycle returns the last divisor.
+func (r DivisorRule) Cycle() int {
+	return r[len(r)-1]
+}
+
+// CycleRule is a leap rule of a table, year y is leap if y modulo Length is
+// in Leaps.
+type CycleRule struct {
+	Length int
+	Leaps  []int
+}
+
+// IsLeap returns true if year y is a leap year.
+func (r CycleRule) IsLeap(y int) bool {
+	return containsInt(r.Leaps, y-floorDiv(y, r.Length)*r.Length)
+}
+
+// Cycle returns the length of the table.
+func (r CycleRule) Cycle() int {
+	return r.Length
+}
+
+// OffsetRule shifts a leap rule by Offset years, i.e. year y is leap if year
+// y+Offset is leap in Rule.
+type OffsetRule struct {
+	Rule   LeapRule
+	Offset int
+}
+
+// IsLeap returns true if year y is a leap year.
+func (r OffsetRule) IsLeap(y int) bool {
+	return r.Rule.IsLeap(y + r.Offset)
+}
+
+// Cycle returns the cycle of Rule.
+func (r OffsetRule) Cycle() int {
+	return r.Rule.Cycle()
+}
+
+// The leap rules of the calendars of this package and some alternatives.
+var (
+	JulianLeapRule    LeapRule = DivisorRule{4}
+	GregorianLeapRule LeapRule = DivisorRule{4, 100, 400}
+	GongheLeapRule    LeapRule = DivisorRule{4, 100, 500}
+	// GHCLeapRule is 4/128 rule, year y is leap if y+1 is divisible by 4 but
+	// not by 128, see GHCDate.
+	GHCLeapRule LeapRule = OffsetRule{DivisorRule{4, 128}, 1}
+	// Cycle33LeapRule has 8 leap years in 33 years, as the arithmetic Persian
+	// calendars.
+	Cycle33LeapRule LeapRule = CycleRule{33, []int{1, 5, 9, 13, 17, 22, 26, 30}}
+)
+
+// gongheMonthDays are the days of months of Gonghe calendar in common years.
+var gongheMonthDays = []int{30, 31, 30, 31, 30, 31, 30, 31, 30, 31, 30, 30}
+
+// SolarCalendar is a Gonghe-style solar calendar of 365 days, plus a leap day
+// at the end of leap years, built from an epoch and a leap rule.
+//
+// Years are numbered continuously, i.e. year 0 is the year before year 1.
+type SolarCalendar struct {
+	name      string
+	epoch     float64
+	rule      LeapRule
+	months    []int
+	cycle     int
+	cycleDays int
+	leaps     []int // leaps[k] is the number of leap years in the first k years of a cycle
+}
+
+// NewSolarCalendar returns the solar calendar whose year 1 begins at the
+// Julian date epoch, with the leap rule and the months of Gonghe calendar.
+//
+// For example, NewSolarCalendar("Gonghe", JDOfGongheFirstDay, GongheLeapRule)
+// is the same as GongheCalendar.
+func NewSolarCalendar(name string, epoch float64, rule LeapRule) *SolarCalendar {
+	c := &SolarCalendar{name: name, epoch: epoch, rule: rule, months: gongheMonthDays}
+	c.cycle = rule.Cycle()
+	c.leaps = make([]int, c.cycle+1)
+	for k := 0; k < c.cycle; k++ {
+		c.leaps[k+1] = c.leaps[k]
+		if rule.IsLeap(k + 1) {
+			c.leaps[k+1]++
+		}
+	}
+	c.cycleDays = c.cycle*365 + c.leaps[c.cycle]
+	return c
+}
+
+// WithMonths returns a copy of c with the days of months in common years,
+// the leap day is added to the last month. It panics if the days are not 365
+// in total.
+func (c *SolarCalendar) WithMonths(days ...int) *SolarCalendar {
+	sum := 0
+	for _, d := range days {
+		sum += d
+	}
+	if sum != 365 || len(days) == 0 {
+		panic(fmt.Sprintf("zcal: %d days in months of SolarCalendar", sum))
+	}
+	n := *c
+	n.months = append([]int(nil), days...)
+	return &n
+}
+
+// Name returns the name of the calendar.
+func (c *SolarCalendar) Name() string { return c.name }
+
+// IsLeapYear returns true if year is a leap year.
+func (c *SolarCalendar) IsLeapYear(year int) bool { return c.rule.IsLeap(year) }
+
+// MonthsInYear returns the number of months.
+func (c *SolarCalendar) MonthsInYear(year int) int { return len(c.months) }
+
+// DaysInMonth returns the number of days of the month, or 0 if the month is
+// out of range.
+func (c *SolarCalendar) DaysInMonth(year, month int) int {
+	if month < 1 || month > len(c.months) {
+		return 0
+	}
+	d := c.months[month-1]
+	if month == len(c.months) && c.IsLeapYear(year) {
+		d++
+	}
+	return d
+}
+
+// Validate checks the date.
+func (c *SolarCalendar) Validate(year, month, day int) error {
+	if month < 1 || month > len(c.months) {
+		return &DateError{c.name, year, month, day, ErrInvalidMonth}
+	}
+	if day < 1 || day > c.DaysInMonth(year, month) {
+		return &DateError{c.name, year, month, day, ErrInvalidDay}
+	}
+	return nil
+}
+
+// daysBefore returns the number of days from the epoch to the first day of
+// year.
+func (c *SolarCalendar) daysBefore(year int) int {
+	n := year - 1
+	q := floorDiv(n, c.cycle)
+	k := n - q*c.cycle
+	return q*c.cycleDays + k*365 + c.leaps[k]
+}
+
+// ToJD converts the date to Julian date.
+func (c *SolarCalendar) ToJD(year, month, day int) float64 {
+	days := c.daysBefore(year) + day - 1
+	for m := 1; m < month && m <= len(c.months); m++ {
+		days += c.months[m-1]
+	}
+	return c.epoch + float64(days)
+}
+
+// FromJD converts Julian date to the date.
+func (c *SolarCalendar) FromJD(jd float64) (year, month, day int) {
+	g := int(math.Floor(jd - c.epoch))
+	q := floorDiv(g, c.cycleDays)
+	r := g - q*c.cycleDays
+	// k is the year in the cycle, whose first day is not after r
+	k := r * c.cycle / c.cycleDays
+	for k < c.cycle && (k+1)*365+c.leaps[k+1] <= r {
+		k++
+	}
+	for k > 0 && k*365+c.leaps[k] > r {
+		k--
+	}
+	year = q*c.cycle + k + 1
+	day = r - k*365 - c.leaps[k] + 1
+	for month = 1; month < len(c.months) && day > c.months[month-1]; month++ {
+		day -= c.months[month-1]
+	}
+	return
+}
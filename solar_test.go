@@ -0,0 +1,94 @@
+package zcal_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/tzengyuxio/zcal"
+)
+
+func TestLeapRule(t *testing.T) {
+	for _, pair := range []struct {
+		rule  LeapRule
+		years []int
+		leaps []bool
+	}{
+		{GregorianLeapRule, []int{1900, 2000, 2016, 2017, 0, -4}, []bool{false, true, true, false, true, true}},
+		{GongheLeapRule, []int{2800, 3000, 2860, 0}, []bool{false, true, true, true}},
+		{GHCLeapRule, []int{3, 127, 251, -1}, []bool{true, false, true, false}},
+		{Cycle33LeapRule, []int{1, 2, 34, 30, -3, -32}, []bool{true, false, true, true, true, true}},
+	} {
+		for i, y := range pair.years {
+			assert.Equal(t, pair.leaps[i], pair.rule.IsLeap(y), "For %v year %d", pair.rule, y)
+		}
+	}
+	for y := -1000; y < 3000; y++ {
+		assert.Equal(t, LeapYearGonghe(y), GongheLeapRule.IsLeap(y))
+		assert.Equal(t, LeapYearGHC(y+1), GHCLeapRule.IsLeap(y))
+		assert.Equal(t, LeapYearGregorian(y), GregorianLeapRule.IsLeap(y))
+	}
+}
+
+func TestSolarCalendar(t *testing.T) {
+	for _, pair := range []struct {
+		solar *SolarCalendar
+		c     Calendar
+	}{
+		{NewSolarCalendar("Gonghe", JDOfGongheFirstDay, GongheLeapRule), GongheCalendar{}},
+		{NewSolarCalendar("GHC", GHCToJD(1, 1, 1), GHCLeapRule), GHCCalendar{}},
+	} {
+		s, c := pair.solar, pair.c
+		for jd := GregorianCalendarToJD(-3000, 1, 1); jd < GregorianCalendarToJD(5000, 1, 1); jd += 3 {
+			y, m, d := s.FromJD(jd)
+			cy, cm, cd := c.FromJD(jd)
+			if y != cy || m != cm || d != cd {
+				t.Fatalf("%s FromJD(%.1f) = %d-%02d-%02d, expected %d-%02d-%02d", s.Name(), jd, y, m, d, cy, cm, cd)
+			}
+			if got := s.ToJD(y, m, d); got != jd {
+				t.Fatalf("%s ToJD(%d, %d, %d) = %.1f, expected %.1f", s.Name(), y, m, d, got, jd)
+			}
+			if got := c.ToJD(y, m, d); got != jd {
+				t.Fatalf("%s ToJD(%d, %d, %d) = %.1f, expected %.1f", c.Name(), y, m, d, got, jd)
+			}
+		}
+		// ToJD of both on the first and the last days of the negative years
+		for y := -3000; y < 1; y++ {
+			for _, md := range [][2]int{{1, 1}, {12, c.DaysInMonth(y, 12)}} {
+				jd := c.ToJD(y, md[0], md[1])
+				assert.Equal(t, jd, s.ToJD(y, md[0], md[1]), "For %s %d-%02d-%02d", c.Name(), y, md[0], md[1])
+				cy, cm, cd := s.FromJD(jd)
+				assert.Equal(t, [3]int{y, md[0], md[1]}, [3]int{cy, cm, cd})
+			}
+		}
+		for y := -100; y < 100; y++ {
+			assert.Equal(t, c.IsLeapYear(y), s.IsLeapYear(y))
+			assert.Equal(t, c.DaysInMonth(y, 12), s.DaysInMonth(y, 12))
+		}
+	}
+}
+
+func TestSolarCalendarMonths(t *testing.T) {
+	// 6 months of 31 days, 5 of 30 days, and 29 days plus the leap day
+	s := NewSolarCalendar("Solar Hijri", GregorianCalendarToJD(622, 3, 22), Cycle33LeapRule).
+		WithMonths(31, 31, 31, 31, 31, 31, 30, 30, 30, 30, 30, 29)
+	assert.Equal(t, 12, s.MonthsInYear(1))
+	assert.Equal(t, 30, s.DaysInMonth(1, 12))
+	assert.Equal(t, 29, s.DaysInMonth(2, 12))
+	assert.Equal(t, 0, s.DaysInMonth(2, 13))
+	y, m, d := s.FromJD(s.ToJD(1, 12, 30))
+	assert.Equal(t, [3]int{1, 12, 30}, [3]int{y, m, d})
+	y, m, d = s.FromJD(s.ToJD(1, 12, 30) + 1)
+	assert.Equal(t, [3]int{2, 1, 1}, [3]int{y, m, d})
+	y, m, d = s.FromJD(s.ToJD(0, 1, 1) - 1)
+	assert.Equal(t, [3]int{-1, 12, 29}, [3]int{y, m, d})
+
+	assert.True(t, errors.Is(s.Validate(2, 12, 30), ErrInvalidDay))
+	assert.True(t, errors.Is(s.Validate(2, 0, 1), ErrInvalidMonth))
+	assert.NoError(t, s.Validate(5, 12, 30))
+
+	assert.Panics(t, func() { s.WithMonths(30, 30) })
+
+	var c Calendar = s
+	assert.Equal(t, "Solar Hijri", c.Name())
+}
@@ -0,0 +1,146 @@
+package zcal
+
+import (
+	"math"
+
+	"github.com/soniakeys/unit"
+)
+
+// SolarTerm is one of the 24 solar terms (二十四節氣), counted from 立春.
+type SolarTerm int
+
+// The 24 solar terms, in the order of a solar year begins at 立春.
+const (
+	LiChun      SolarTerm = iota // 立春
+	YuShui                       // 雨水
+	JingZhe                      // 驚蟄
+	ChunFen                      // 春分
+	QingMing                     // 清明
+	GuYu                         // 穀雨
+	LiXia                        // 立夏
+	XiaoMan                      // 小滿
+	MangZhong                    // 芒種
+	XiaZhi                       // 夏至
+	XiaoShu                      // 小暑
+	DaShu                        // 大暑
+	LiQiu                        // 立秋
+	ChuShu                       // 處暑
+	BaiLu                        // 白露
+	QiuFen                       // 秋分
+	HanLu                        // 寒露
+	ShuangJiang                  // 霜降
+	LiDong                       // 立冬
+	XiaoXue                      // 小雪
+	DaXue                        // 大雪
+	DongZhi                      // 冬至
+	XiaoHan                      // 小寒
+	DaHan                        // 大寒
+)
+
+var solarTermNames = []string{
+	"立春", "雨水", "驚蟄", "春分", "清明", "穀雨",
+	"立夏", "小滿", "芒種", "夏至", "小暑", "大暑",
+	"立秋", "處暑", "白露", "秋分", "寒露", "霜降",
+	"立冬", "小雪", "大雪", "冬至", "小寒", "大寒",
+}
+
+func (s SolarTerm) String() string {
+	return solarTermNames[s.mod()]
+}
+
+func (s SolarTerm) mod() int {
+	i := int(s) % 24
+	if i < 0 {
+		i += 24
+	}
+	return i
+}
+
+// Longitude returns the apparent solar longitude of the term in degrees, e.g.
+// 315 for 立春 and 0 for 春分.
+func (s SolarTerm) Longitude() float64 {
+	return math.Mod(315+15*float64(s.mod()), 360)
+}
+
+// IsZhongqi returns true if the term is a principal term (中氣), whose
+// longitude is a multiple of 30°. The others are sectional terms (節).
+func (s SolarTerm) IsZhongqi() bool {
+	return s.mod()%2 == 1
+}
+
+// SolarTermInstant is the moment of a solar term.
+type SolarTermInstant struct {
+	Term SolarTerm
+	JD   float64 // local time, i.e. UT plus time zone offset
+}
+
+// Date returns the local Western calendar date of the solar term.
+func (s SolarTermInstant) Date() (year, month, day int) {
+	return DefaultWesternCalendar.FromJD(s.JD)
+}
+
+// GongheDate returns the local Gonghe calendar date of the solar term.
+func (s SolarTermInstant) GongheDate() GongheDate {
+	return GongheDateFromJD(s.JD)
+}
+
+// SolarTermJD returns the instant of the solar term in the solar year which
+// begins at 立春 of year (astronomical year numbering), i.e. 小寒 and 大寒
+// are in January of the next year.
+//
+// The result is the local time of tz hours offset, i.e. ΔT is subtracted from
+// the JDE and tz/24 is added, which is the same as the results of
+// CalcDongzhiAndShuo. A nil e means the default ephemeris.
+func SolarTermJD(e Ephemeris, year int, term SolarTerm, tz float64) float64 {
+	return float64(SolarTermTT(e, year, term).UT().Local(tz))
+}
+
+// SolarTermTT returns the instant in Terrestrial Time of the solar term, see
+// SolarTermJD.
+func SolarTermTT(e Ephemeris, year int, term SolarTerm) TT {
+	λ := term.Longitude()
+	// days after the March equinox
+	days := math.Mod(λ+45, 360) - 45
+	jde := GregorianCalendarToJD(year, 3, 20) + days*365.2422/360
+	return TT(solveSolarLongitude(e, unit.AngleFromDeg(λ), jde))
+}
+
+// SolarTerms returns the instants of all 24 solar terms from 立春 of year to
+// 大寒 of the next year. The results are meaningless for the years out of
+// MinYear to MaxYear, see SolarTermsChecked.
+func SolarTerms(e Ephemeris, year int, tz float64) []SolarTermInstant {
+	terms := make([]SolarTermInstant, 24)
+	for i := range terms {
+		s := SolarTerm(i)
+		terms[i] = SolarTermInstant{s, SolarTermJD(e, year, s, tz)}
+	}
+	return terms
+}
+
+// SolarTermsChecked is like SolarTerms but returns an error wrapping
+// ErrYearRange if year is out of MinYear to MaxYear.
+func SolarTermsChecked(e Ephemeris, year int, tz float64) ([]SolarTermInstant, error) {
+	if err := checkYear(year); err != nil {
+		return nil, err
+	}
+	return SolarTerms(e, year, tz), nil
+}
+
+// maxIterations bounds the iterations of solveSolarLongitude, which converges
+// in a few iterations in the supported years.
+const maxIterations = 50
+
+// solveSolarLongitude finds the JDE near jde when the apparent solar longitude
+// is q, see solstice.December2.
+func solveSolarLongitude(e Ephemeris, q unit.Angle, jde float64) float64 {
+	e = ephemeris(e)
+	for i := 0; i < maxIterations; i++ {
+		λ, _, _ := ApparentSun(e, jde)
+		c := 58 * (q - λ).Sin() // (27.1) p. 180
+		jde += c
+		if math.Abs(c) < .000005 {
+			break
+		}
+	}
+	return jde
+}
@@ -0,0 +1,74 @@
+package zcal_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/tzengyuxio/zcal"
+)
+
+func TestSolarTerm(t *testing.T) {
+	for _, pair := range []struct {
+		term      SolarTerm
+		name      string
+		longitude float64
+		zhongqi   bool
+	}{
+		{LiChun, "立春", 315, false},
+		{YuShui, "雨水", 330, true},
+		{ChunFen, "春分", 0, true},
+		{QingMing, "清明", 15, false},
+		{XiaZhi, "夏至", 90, true},
+		{QiuFen, "秋分", 180, true},
+		{DongZhi, "冬至", 270, true},
+		{XiaoHan, "小寒", 285, false},
+		{DaHan, "大寒", 300, true},
+	} {
+		assert.Equal(t, pair.name, pair.term.String())
+		assert.Equal(t, pair.longitude, pair.term.Longitude(), "For %s", pair.name)
+		assert.Equal(t, pair.zhongqi, pair.term.IsZhongqi(), "For %s", pair.name)
+	}
+}
+
+func TestSolarTerms(t *testing.T) {
+	e := DefaultEphemeris()
+
+	// 2017 年節氣，東八區
+	terms := SolarTerms(e, 2017, 8)
+	assert.Len(t, terms, 24)
+	for i, date := range [][3]int{
+		{2017, 2, 3}, {2017, 2, 18}, {2017, 3, 5}, {2017, 3, 20},
+		{2017, 4, 4}, {2017, 4, 20}, {2017, 5, 5}, {2017, 5, 21},
+		{2017, 6, 5}, {2017, 6, 21}, {2017, 7, 7}, {2017, 7, 22},
+		{2017, 8, 7}, {2017, 8, 23}, {2017, 9, 7}, {2017, 9, 23},
+		{2017, 10, 8}, {2017, 10, 23}, {2017, 11, 7}, {2017, 11, 22},
+		{2017, 12, 7}, {2017, 12, 22}, {2018, 1, 5}, {2018, 1, 20},
+	} {
+		assert.Equal(t, SolarTerm(i), terms[i].Term)
+		y, m, d := terms[i].Date()
+		assert.Equal(t, date, [3]int{y, m, d}, "For %s", terms[i].Term)
+	}
+
+	// 立春 2017-02-03 23:34 (UTC+8)
+	assert.InDelta(t, GregorianCalendarToJD(2017, 2, 3)+(23+34.0/60)/24, terms[LiChun].JD, 2.0/1440)
+
+	// 洪武十七年冬至
+	s, _ := CalcDongzhiAndShuo(e, 1384, 8)
+	assert.InDelta(t, s, SolarTermJD(e, 1384, DongZhi, 8), 1.0/86400)
+	assert.Equal(t, NewGongheDate(2225, 11, 19), SolarTerms(e, 1384, 8)[DongZhi].GongheDate())
+
+	// out of the supported years
+	for _, year := range []int{MinYear - 1, MaxYear + 1, 100000} {
+		_, err := SolarTermsChecked(e, year, 8)
+		assert.True(t, errors.Is(err, ErrYearRange), "For %d", year)
+		assert.Len(t, SolarTerms(e, year, 8), 24)
+	}
+	for _, year := range []int{MinYear, MaxYear} {
+		terms, err := SolarTermsChecked(e, year, 8)
+		assert.NoError(t, err)
+		for i := 1; i < len(terms); i++ {
+			assert.InDelta(t, 15.2, terms[i].JD-terms[i-1].JD, 1.5, "For %d %s", year, terms[i].Term)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package zcal
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// JD is a Julian date in Universal Time, i.e. the days since the noon of
+// January 1, 4713 BC (proleptic Julian calendar) at Greenwich.
+type JD float64
+
+// TT is a Julian ephemeris date (JDE) in Terrestrial Time, the uniform time
+// scale of the ephemerides, e.g. the instants of solar terms and new moons
+// before they are converted to civil time.
+type TT float64
+
+// LocalJD is a Julian date in local civil time, i.e. UT plus a time zone
+// offset. Its day begins at .5 as the dates of the calendar functions, e.g.
+// JDToGongheCalendar.
+type LocalJD float64
+
+// UT returns the Julian date in Universal Time of t, see DeltaT.
+func (t TT) UT() JD {
+	return JD(float64(t) - DeltaT(decimalYear(float64(t)))/secondsOfDay)
+}
+
+// TT returns the Julian ephemeris date of j, see DeltaT.
+func (j JD) TT() TT {
+	return TT(float64(j) + DeltaT(decimalYear(float64(j)))/secondsOfDay)
+}
+
+// Local returns the local time of j at the offset of tz hours.
+func (j JD) Local(tz float64) LocalJD {
+	return LocalJD(float64(j) + tz/24)
+}
+
+// UT returns the Universal Time of l at the offset of tz hours.
+func (l LocalJD) UT(tz float64) JD {
+	return JD(float64(l) - tz/24)
+}
+
+// jdOfUnixEpoch is 1970-01-01T00:00:00Z.
+const jdOfUnixEpoch = 2440587.5
+
+// The range of Julian date could be converted to time.Time, from JD 0 to the
+// end of year 9999, the last year time.Time could format as RFC 3339.
+const (
+	MinTimeJD JD = 0
+	MaxTimeJD JD = 5373484.5 // 10000-01-01T00:00:00Z
+)
+
+// ErrTimeRange means the Julian date is out of [MinTimeJD, MaxTimeJD).
+var ErrTimeRange = errors.New("zcal: Julian date out of time.Time range")
+
+// FromTime returns the Julian date of t.
+func FromTime(t time.Time) JD {
+	sec := t.Unix()
+	days := floorDiv64(sec, int64(secondsOfDay))
+	sec -= days * int64(secondsOfDay)
+	f := (float64(sec) + float64(t.Nanosecond())/1e9) / secondsOfDay
+	return JD(jdOfUnixEpoch + float64(days) + f)
+}
+
+// ToTime returns the time of j in location loc. The result is rounded to
+// millisecond since the precision of a float64 Julian date is about 40
+// microseconds.
+func (j JD) ToTime(loc *time.Location) (time.Time, error) {
+	if math.IsNaN(float64(j)) || j < MinTimeJD || j >= MaxTimeJD {
+		return time.Time{}, ErrTimeRange
+	}
+	days, f := depart(float64(j) - jdOfUnixEpoch)
+	ms := int64(math.Floor(f*secondsOfDay*1000 + .5))
+	sec := int64(days)*int64(secondsOfDay) + ms/1000
+	return time.Unix(sec, (ms%1000)*int64(time.Millisecond)).In(loc), nil
+}
+
+// dateToTime returns the midnight of the day of jd in location loc.
+func dateToTime(jd float64, loc *time.Location) (time.Time, error) {
+	if JD(jd) < MinTimeJD || JD(jd) >= MaxTimeJD {
+		return time.Time{}, ErrTimeRange
+	}
+	// time.Time uses proleptic Gregorian calendar
+	y, m, d, _ := JDToGregorianCalendar(jd)
+	return time.Date(y, time.Month(m), d, 0, 0, 0, 0, loc), nil
+}
+
+// dateOfTime returns the Julian date of the beginning of the day of t in its
+// location, the clock of t is ignored.
+func dateOfTime(t time.Time) float64 {
+	y, m, d := t.Date()
+	return GregorianCalendarToJD(y, int(m), d)
+}
+
+// GongheDateFromTime returns the Gonghe date of t in its location.
+func GongheDateFromTime(t time.Time) GongheDate {
+	return GongheDateFromJD(dateOfTime(t))
+}
+
+// ToTime returns the midnight of g in location loc.
+func (g GongheDate) ToTime(loc *time.Location) (time.Time, error) {
+	return dateToTime(g.JD(), loc)
+}
+
+// GHCDateFromTime returns the GHC date of t in its location.
+func GHCDateFromTime(t time.Time) GHCDate {
+	return GHCDateFromJD(dateOfTime(t))
+}
+
+// ToTime returns the midnight of g in location loc.
+func (g GHCDate) ToTime(loc *time.Location) (time.Time, error) {
+	return dateToTime(g.JD(), loc)
+}
+
+// FromTime returns the date in w of t in its location.
+func (w WesternCalendar) FromTime(t time.Time) (year, month, day int) {
+	return w.FromJD(dateOfTime(t))
+}
+
+// ToTime returns the midnight of the date in w in location loc. An error is
+// returned if the date is invalid in w.
+func (w WesternCalendar) ToTime(y, m, d int, loc *time.Location) (time.Time, error) {
+	if err := w.Validate(y, m, d); err != nil {
+		return time.Time{}, err
+	}
+	return dateToTime(w.ToJD(y, m, d), loc)
+}
+
+// LunarDateFromTime returns the Chinese lunar calendar date of the date of t
+// in its location. A nil e means the default ephemeris.
+func LunarDateFromTime(e Ephemeris, t time.Time) LunarDate {
+	return LunarDateFromJD(e, dateOfTime(t))
+}
+
+// ToTime returns the midnight of l in location loc. An error is returned if l
+// does not exist, see ChineseLunarToJD. A nil e means the default ephemeris.
+func (l LunarDate) ToTime(e Ephemeris, loc *time.Location) (time.Time, error) {
+	jd, err := ChineseLunarToJD(e, l.Year, l.Month, l.IsLeap, l.Day)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return dateToTime(jd, loc)
+}
+
+func floorDiv64(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
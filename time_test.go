@@ -0,0 +1,131 @@
+package zcal_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/tzengyuxio/zcal"
+)
+
+func TestJDTime(t *testing.T) {
+	utc8 := time.FixedZone("UTC+8", 8*60*60)
+	for _, pair := range []struct {
+		t  time.Time
+		jd JD
+	}{
+		{time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), 2440587.5},
+		{time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC), 2451545},
+		{time.Date(2000, 1, 1, 20, 0, 0, 0, utc8), 2451545},
+		{time.Date(1582, 10, 15, 0, 0, 0, 0, time.UTC), 2299160.5},
+		{time.Date(1957, 10, 4, 19, 26, 24, 0, time.UTC), 2436116.31},
+		{time.Date(-4713, 11, 24, 12, 0, 0, 0, time.UTC), 0}, // proleptic Gregorian
+	} {
+		assert.InDelta(t, float64(pair.jd), float64(FromTime(pair.t)), 1e-9, "For time %v", pair.t)
+		tm, err := pair.jd.ToTime(pair.t.Location())
+		assert.NoError(t, err)
+		assert.True(t, pair.t.Equal(tm), "For JD %v expected %v got %v", pair.jd, pair.t, tm)
+		assert.Equal(t, pair.t.Location(), tm.Location())
+	}
+
+	// rounded to millisecond
+	tm := time.Date(2017, 2, 3, 23, 34, 56, 789000000, time.UTC)
+	back, err := FromTime(tm).ToTime(time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, tm, back)
+
+	// the day fraction of JDToGregorianCalendar counts from midnight
+	jd := FromTime(tm)
+	y, m, d, f := JDToGregorianCalendar(float64(jd))
+	assert.Equal(t, [3]int{2017, 2, 3}, [3]int{y, m, d})
+	assert.InDelta(t, (23*3600+34*60+56.789)/86400, f, 1e-9)
+
+	for _, jd := range []JD{-1, MaxTimeJD, JD(math.NaN()), JD(math.Inf(1)), JD(math.Inf(-1))} {
+		_, err := jd.ToTime(time.UTC)
+		assert.Equal(t, ErrTimeRange, err, "For JD %v", jd)
+	}
+	_, err = (MaxTimeJD - 1).ToTime(time.UTC)
+	assert.NoError(t, err)
+}
+
+func TestDateTime(t *testing.T) {
+	utc8 := time.FixedZone("UTC+8", 8*60*60)
+
+	// 2017-02-03 23:34 UTC+8 is 2017-02-03 15:34 UTC, the date is local
+	tm := time.Date(2017, 2, 3, 23, 34, 0, 0, utc8)
+	g := GongheDateFromTime(tm)
+	y, m, d := WesternCalendarToGongheCalendar(2017, 2, 3)
+	assert.Equal(t, NewGongheDate(y, m, d), g)
+	assert.Equal(t, g, GongheDateFromTime(tm.In(time.UTC)))
+	assert.Equal(t, g.AddDays(1), GongheDateFromTime(tm.Add(time.Hour)))
+
+	mid, err := g.ToTime(utc8)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2017, 2, 3, 0, 0, 0, 0, utc8), mid)
+
+	h := GHCDateFromTime(tm)
+	y, m, d = WesternCalendarToGHC(2017, 2, 3)
+	assert.Equal(t, NewGHCDate(y, m, d), h)
+	mid, err = h.ToTime(time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2017, 2, 3, 0, 0, 0, 0, time.UTC), mid)
+
+	// 共和元年立春, proleptic Gregorian in time.Time
+	mid, err = NewGongheDate(1, 1, 1).ToTime(time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(-840, 2, 4, 0, 0, 0, 0, time.UTC), mid)
+	assert.Equal(t, NewGongheDate(1, 1, 1), GongheDateFromTime(mid))
+
+	_, err = NewGongheDate(12000, 1, 1).ToTime(time.UTC)
+	assert.Equal(t, ErrTimeRange, err)
+
+	// 2017-02-03 is 丁酉年正月初七
+	l := LunarDateFromTime(nil, tm)
+	assert.Equal(t, LunarDate{2017, 1, false, 7}, l)
+	assert.Equal(t, l, LunarDateFromTime(nil, tm.In(time.UTC)))
+	mid, err = l.ToTime(nil, utc8)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2017, 2, 3, 0, 0, 0, 0, utc8), mid)
+	mid, err = LunarDate{2017, 6, true, 1}.ToTime(nil, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2017, 7, 23, 0, 0, 0, 0, time.UTC), mid)
+	_, err = LunarDate{2017, 5, true, 1}.ToTime(nil, time.UTC)
+	assert.True(t, errors.Is(err, ErrInvalidMonth))
+}
+
+func TestWesternCalendarTime(t *testing.T) {
+	// the Western calendar is Julian before the reform, time.Time is not
+	tm := time.Date(1582, 10, 14, 12, 0, 0, 0, time.UTC)
+	y, m, d := DefaultWesternCalendar.FromTime(tm)
+	assert.Equal(t, [3]int{1582, 10, 4}, [3]int{y, m, d})
+	y, m, d = ProlepticGregorianCalendar.FromTime(tm)
+	assert.Equal(t, [3]int{1582, 10, 14}, [3]int{y, m, d})
+	// 共和元年立春
+	y, m, d = DefaultWesternCalendar.FromTime(time.Date(-840, 2, 4, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, [3]int{-841, 2, 12}, [3]int{y, m, d})
+
+	for _, pair := range []struct {
+		y, m, d int
+		t       time.Time
+	}{
+		{2017, 2, 3, time.Date(2017, 2, 3, 0, 0, 0, 0, time.UTC)},
+		{1582, 10, 15, time.Date(1582, 10, 15, 0, 0, 0, 0, time.UTC)},
+		{1582, 10, 4, time.Date(1582, 10, 14, 0, 0, 0, 0, time.UTC)},
+		{-1, 12, 31, time.Date(0, 12, 29, 0, 0, 0, 0, time.UTC)},
+	} {
+		mid, err := DefaultWesternCalendar.ToTime(pair.y, pair.m, pair.d, time.UTC)
+		assert.NoError(t, err)
+		assert.Equal(t, pair.t, mid, "For %d-%02d-%02d", pair.y, pair.m, pair.d)
+		y, m, d := DefaultWesternCalendar.FromTime(mid)
+		assert.Equal(t, [3]int{pair.y, pair.m, pair.d}, [3]int{y, m, d})
+	}
+
+	_, err := DefaultWesternCalendar.ToTime(1582, 10, 10, time.UTC)
+	assert.True(t, errors.Is(err, ErrNonexistentDate))
+	_, err = DefaultWesternCalendar.ToTime(0, 1, 1, time.UTC)
+	assert.True(t, errors.Is(err, ErrYearZero))
+	_, err = DefaultWesternCalendar.ToTime(10000, 1, 1, time.UTC)
+	assert.Equal(t, ErrTimeRange, err)
+}
@@ -0,0 +1,205 @@
+package zcal
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by the validating conversion functions, they are wrapped in
+// a *DateError and could be tested with errors.Is.
+var (
+	// ErrInvalidMonth means the month is out of range of the calendar.
+	ErrInvalidMonth = errors.New("invalid month")
+	// ErrInvalidDay means the day is out of range of the month.
+	ErrInvalidDay = errors.New("invalid day")
+	// ErrNonexistentDate means the date is skipped by the Gregorian reform,
+	// i.e. 1582-10-05 to 1582-10-14 in Western calendar.
+	ErrNonexistentDate = errors.New("nonexistent date")
+	// ErrYearZero means the year 0 is given in Western calendar, which has no
+	// "0 year".
+	ErrYearZero = errors.New("year zero")
+	// ErrYearRange means the year is out of MinYear to MaxYear, the range of
+	// the astronomical calculations, e.g. solar terms and lunar calendar.
+	ErrYearRange = errors.New("year out of range")
+)
+
+// MinYear and MaxYear are the range of the years, in astronomical year
+// numbering, supported by the astronomical calculations. The ephemeris and
+// ΔT are extrapolated far beyond the range of the observations in these
+// years, so the results are approximate, but the calculations converge.
+const (
+	MinYear = -20000
+	MaxYear = 20000
+)
+
+// checkYear returns an error if year is out of MinYear to MaxYear.
+func checkYear(year int) error {
+	if year < MinYear || year > MaxYear {
+		return fmt.Errorf("zcal: year %d: %w", year, ErrYearRange)
+	}
+	return nil
+}
+
+// DateError records an invalid date and the reason why it is invalid.
+type DateError struct {
+	Calendar         string
+	Year, Month, Day int
+	Err              error
+}
+
+func (e *DateError) Error() string {
+	return fmt.Sprintf("zcal: %s date %d-%02d-%02d: %v", e.Calendar, e.Year, e.Month, e.Day, e.Err)
+}
+
+// Unwrap returns the underlying error, e.g. ErrInvalidDay.
+func (e *DateError) Unwrap() error {
+	return e.Err
+}
+
+var daysOfMonth = []int{31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+
+func validateMonthDay(name string, y, m, d int, leap bool) error {
+	if m < 1 || m > 12 {
+		return &DateError{name, y, m, d, ErrInvalidMonth}
+	}
+	n := daysOfMonth[m-1]
+	if m == 2 && leap {
+		n++
+	}
+	if d < 1 || d > n {
+		return &DateError{name, y, m, d, ErrInvalidDay}
+	}
+	return nil
+}
+
+func validateGongheMonthDay(name string, y, m, d int, leap bool) error {
+	if m < 1 || m > 12 {
+		return &DateError{name, y, m, d, ErrInvalidMonth}
+	}
+	if d < 1 || d > daysInGongheMonth(m, leap) {
+		return &DateError{name, y, m, d, ErrInvalidDay}
+	}
+	return nil
+}
+
+// ValidateGregorianCalendar checks the Gregorian calendar date, year is in
+// astronomical year numbering.
+func ValidateGregorianCalendar(year, month, day int) error {
+	return validateMonthDay("Gregorian", year, month, day, LeapYearGregorian(year))
+}
+
+// ValidateJulianCalendar checks the Julian calendar date, year is in
+// astronomical year numbering.
+func ValidateJulianCalendar(year, month, day int) error {
+	return validateMonthDay("Julian", year, month, day, LeapYearJulian(year))
+}
+
+// ValidateWesternCalendar checks the Western calendar date, which has no year
+// 0 and no days between 1582-10-05 and 1582-10-14.
+func ValidateWesternCalendar(year, month, day int) error {
+	return DefaultWesternCalendar.Validate(year, month, day)
+}
+
+// ValidateGongheCalendar checks the Gonghe calendar date.
+func ValidateGongheCalendar(year, month, day int) error {
+	return validateGongheMonthDay("Gonghe", year, month, day, LeapYearGonghe(year))
+}
+
+// ValidateGHC checks the Gonghe calendar date with 128-leap-rule.
+func ValidateGHC(year, month, day int) error {
+	return validateGongheMonthDay("GHC", year, month, day, LeapYearGHC(year+1))
+}
+
+// Validate checks if g is a valid Gonghe calendar date.
+func (g GongheDate) Validate() error {
+	return ValidateGongheCalendar(g.Year, g.Month, g.Day)
+}
+
+// Validate checks if g is a valid GHC date.
+func (g GHCDate) Validate() error {
+	return ValidateGHC(g.Year, g.Month, g.Day)
+}
+
+// GregorianCalendarToJDChecked is like GregorianCalendarToJD but returns an
+// error for invalid date.
+func GregorianCalendarToJDChecked(year, month, day int) (float64, error) {
+	if err := ValidateGregorianCalendar(year, month, day); err != nil {
+		return 0, err
+	}
+	return GregorianCalendarToJD(year, month, day), nil
+}
+
+// JulianCalendarToJDChecked is like JulianCalendarToJD but returns an error
+// for invalid date.
+func JulianCalendarToJDChecked(year, month, day int) (float64, error) {
+	if err := ValidateJulianCalendar(year, month, day); err != nil {
+		return 0, err
+	}
+	return JulianCalendarToJD(year, month, day), nil
+}
+
+// GongheCalendarToJDChecked is like GongheCalendarToJD but returns an error
+// for invalid date.
+func GongheCalendarToJDChecked(year, month, day int) (float64, error) {
+	if err := ValidateGongheCalendar(year, month, day); err != nil {
+		return 0, err
+	}
+	return GongheCalendarToJD(year, month, day), nil
+}
+
+// GHCToJDChecked is like GHCToJD but returns an error for invalid date.
+func GHCToJDChecked(year, month, day int) (float64, error) {
+	if err := ValidateGHC(year, month, day); err != nil {
+		return 0, err
+	}
+	return GHCToJD(year, month, day), nil
+}
+
+// GongheCalendarToWesternCalendarChecked is like
+// GongheCalendarToWesternCalendar but returns an error for invalid date.
+func GongheCalendarToWesternCalendarChecked(y, m, d int) (year, month, day int, err error) {
+	if err = ValidateGongheCalendar(y, m, d); err != nil {
+		return
+	}
+	year, month, day = GongheCalendarToWesternCalendar(y, m, d)
+	return
+}
+
+// GHCToWesternCalendarChecked is like GHCToWesternCalendar but returns an
+// error for invalid date.
+func GHCToWesternCalendarChecked(y, m, d int) (year, month, day int, err error) {
+	if err = ValidateGHC(y, m, d); err != nil {
+		return
+	}
+	year, month, day = GHCToWesternCalendar(y, m, d)
+	return
+}
+
+// WesternCalendarToGongheCalendarChecked is like
+// WesternCalendarToGongheCalendar but returns an error for invalid date.
+func WesternCalendarToGongheCalendarChecked(y, m, d int) (year, month, day int, err error) {
+	if err = ValidateWesternCalendar(y, m, d); err != nil {
+		return
+	}
+	year, month, day = WesternCalendarToGongheCalendar(y, m, d)
+	return
+}
+
+// WesternCalendarToGHCChecked is like WesternCalendarToGHC but returns an
+// error for invalid date.
+func WesternCalendarToGHCChecked(y, m, d int) (year, month, day int, err error) {
+	if err = ValidateWesternCalendar(y, m, d); err != nil {
+		return
+	}
+	year, month, day = WesternCalendarToGHC(y, m, d)
+	return
+}
+
+// WesternCalendarToStemBranchChecked is like WesternCalendarToStemBranch but
+// returns an error for invalid date.
+func WesternCalendarToStemBranchChecked(y, m, d int) (string, error) {
+	if err := ValidateWesternCalendar(y, m, d); err != nil {
+		return "", err
+	}
+	return WesternCalendarToStemBranch(y, m, d), nil
+}
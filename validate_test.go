@@ -0,0 +1,131 @@
+package zcal_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/tzengyuxio/zcal"
+)
+
+func TestValidateWesternCalendar(t *testing.T) {
+	for _, pair := range []struct {
+		y, m, d int
+		err     error
+	}{
+		{2017, 8, 19, nil},
+		{2000, 2, 29, nil},
+		{1900, 2, 29, ErrInvalidDay},
+		{1500, 2, 29, nil}, // Julian leap year
+		{-1, 2, 29, nil},   // BC 1 is a leap year
+		{-2, 2, 29, ErrInvalidDay},
+		{0, 1, 1, ErrYearZero},
+		{2017, 13, 1, ErrInvalidMonth},
+		{2017, 0, 1, ErrInvalidMonth},
+		{2017, 1, 45, ErrInvalidDay},
+		{2017, 4, 31, ErrInvalidDay},
+		{2017, 4, 0, ErrInvalidDay},
+		{1582, 10, 4, nil},
+		{1582, 10, 5, ErrNonexistentDate},
+		{1582, 10, 14, ErrNonexistentDate},
+		{1582, 10, 15, nil},
+	} {
+		err := ValidateWesternCalendar(pair.y, pair.m, pair.d)
+		if pair.err == nil {
+			assert.NoError(t, err, "For date %04d-%02d-%02d", pair.y, pair.m, pair.d)
+		} else {
+			assert.True(t, errors.Is(err, pair.err), "For date %04d-%02d-%02d expected %v got %v", pair.y, pair.m, pair.d, pair.err, err)
+		}
+	}
+}
+
+func TestValidateGongheCalendar(t *testing.T) {
+	for _, pair := range []struct {
+		y, m, d int
+		err     error
+	}{
+		{1, 1, 1, nil},
+		{1, 1, 30, nil},
+		{1, 1, 31, ErrInvalidDay},
+		{1, 2, 31, nil},
+		{1, 2, 32, ErrInvalidDay},
+		{1, 12, 31, ErrInvalidDay},
+		{4, 12, 31, nil},
+		{0, 12, 31, nil},
+		{100, 12, 31, ErrInvalidDay},
+		{1, 13, 1, ErrInvalidMonth},
+		{1, 0, 1, ErrInvalidMonth},
+		{1, 1, 0, ErrInvalidDay},
+	} {
+		err := ValidateGongheCalendar(pair.y, pair.m, pair.d)
+		if pair.err == nil {
+			assert.NoError(t, err, "For date %04d-%02d-%02d", pair.y, pair.m, pair.d)
+			assert.NoError(t, NewGongheDate(pair.y, pair.m, pair.d).Validate())
+		} else {
+			assert.True(t, errors.Is(err, pair.err), "For date %04d-%02d-%02d expected %v got %v", pair.y, pair.m, pair.d, pair.err, err)
+		}
+	}
+
+	assert.NoError(t, ValidateGHC(3, 12, 31))
+	assert.True(t, errors.Is(ValidateGHC(4, 12, 31), ErrInvalidDay))
+	assert.True(t, errors.Is(NewGHCDate(127, 12, 31).Validate(), ErrInvalidDay))
+}
+
+func TestCheckedConversion(t *testing.T) {
+	jd, err := GongheCalendarToJDChecked(2225, 11, 19)
+	assert.NoError(t, err)
+	assert.Equal(t, JDOfShuodanDongzhi, jd)
+	_, err = GongheCalendarToJDChecked(1, 2, 32)
+	assert.True(t, errors.Is(err, ErrInvalidDay))
+	assert.EqualError(t, err, "zcal: Gonghe date 1-02-32: invalid day")
+
+	jd, err = GregorianCalendarToJDChecked(2000, 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2451544.5, jd)
+	_, err = GregorianCalendarToJDChecked(2000, 13, 1)
+	assert.True(t, errors.Is(err, ErrInvalidMonth))
+
+	jd, err = JulianCalendarToJDChecked(1384, 12, 13)
+	assert.NoError(t, err)
+	assert.Equal(t, JDOfShuodanDongzhi, jd)
+	_, err = JulianCalendarToJDChecked(1383, 2, 29)
+	assert.True(t, errors.Is(err, ErrInvalidDay))
+
+	y, m, d, err := WesternCalendarToGongheCalendarChecked(1384, 12, 13)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2225, 11, 19}, []int{y, m, d})
+	_, _, _, err = WesternCalendarToGongheCalendarChecked(1582, 10, 10)
+	assert.True(t, errors.Is(err, ErrNonexistentDate))
+	_, _, _, err = WesternCalendarToGHCChecked(0, 10, 10)
+	assert.True(t, errors.Is(err, ErrYearZero))
+
+	y, m, d, err = GongheCalendarToWesternCalendarChecked(1, 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{-841, 2, 12}, []int{y, m, d})
+	_, _, _, err = GongheCalendarToWesternCalendarChecked(1, 1, 31)
+	assert.True(t, errors.Is(err, ErrInvalidDay))
+
+	jd, err = GHCToJDChecked(3, 12, 31)
+	assert.NoError(t, err)
+	assert.Equal(t, GHCToJD(4, 1, 1)-1, jd)
+	_, err = GHCToJDChecked(4, 12, 31)
+	assert.True(t, errors.Is(err, ErrInvalidDay))
+	y, m, d, err = GHCToWesternCalendarChecked(2841, 11, 28)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2000, 1, 1}, []int{y, m, d})
+	_, _, _, err = GHCToWesternCalendarChecked(2841, 13, 1)
+	assert.True(t, errors.Is(err, ErrInvalidMonth))
+
+	sb, err := WesternCalendarToStemBranchChecked(1384, 12, 13)
+	assert.NoError(t, err)
+	assert.Equal(t, "甲子", sb)
+	_, err = WesternCalendarToStemBranchChecked(1384, 12, 32)
+	assert.True(t, errors.Is(err, ErrInvalidDay))
+}
+
+func TestWesternCalendarReformBoundary(t *testing.T) {
+	// 1582-10-04 (Julian) is followed by 1582-10-15 (Gregorian)
+	y1, m1, d1 := WesternCalendarToGongheCalendar(1582, 10, 4)
+	y2, m2, d2 := WesternCalendarToGongheCalendar(1582, 10, 15)
+	assert.Equal(t, 1, NewGongheDate(y2, m2, d2).Sub(NewGongheDate(y1, m1, d1)))
+}
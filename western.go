@@ -0,0 +1,143 @@
+package zcal
+
+import (
+	"math"
+	"strings"
+)
+
+// WesternCalendar is the Western calendar which uses Julian calendar before
+// the Gregorian reform and Gregorian calendar since then. The reform date is
+// different by countries, e.g. Britain switched at 1752-09-14 and Russia at
+// 1918-02-14.
+//
+// Years are in Western year numbering, i.e. there is no year 0 and the
+// previous year of 1 is -1. Only the switch of the leap rule is considered,
+// e.g. the year beginning at March 25 in old British records is not.
+type WesternCalendar struct {
+	// ReformJD is the Julian date of the first day in Gregorian calendar.
+	ReformJD float64
+}
+
+var (
+	// DefaultWesternCalendar switches to Gregorian calendar at 1582-10-15,
+	// which is used by the Western calendar functions of this package, e.g.
+	// WesternCalendarToGongheCalendar.
+	DefaultWesternCalendar = WesternCalendar{jdOfGregorianCalendar}
+
+	// ProlepticGregorianCalendar uses Gregorian calendar for all dates.
+	ProlepticGregorianCalendar = WesternCalendar{math.Inf(-1)}
+
+	// ProlepticJulianCalendar uses Julian calendar for all dates.
+	ProlepticJulianCalendar = WesternCalendar{math.Inf(1)}
+)
+
+var westernCalendarOfCountry = map[string]WesternCalendar{
+	"IT": DefaultWesternCalendar,
+	"ES": DefaultWesternCalendar,
+	"PT": DefaultWesternCalendar,
+	"PL": DefaultWesternCalendar,
+	"FR": {GregorianCalendarToJD(1582, 12, 20)},
+	"DK": {GregorianCalendarToJD(1700, 3, 1)},
+	"NO": {GregorianCalendarToJD(1700, 3, 1)},
+	"GB": {GregorianCalendarToJD(1752, 9, 14)},
+	"SE": {GregorianCalendarToJD(1753, 3, 1)}, // the Swedish calendar of 1700-1712 is not supported
+	"RU": {GregorianCalendarToJD(1918, 2, 14)},
+	"GR": {GregorianCalendarToJD(1923, 3, 1)},
+}
+
+// WesternCalendarOfCountry returns the Western calendar of the country by its
+// ISO 3166-1 alpha-2 code, e.g. "GB", "RU" and "SE". The second return value
+// is false if the country is unknown.
+func WesternCalendarOfCountry(code string) (WesternCalendar, bool) {
+	w, ok := westernCalendarOfCountry[strings.ToUpper(code)]
+	return w, ok
+}
+
+// astronomicalYear converts Western year to astronomical year numbering.
+func astronomicalYear(y int) int {
+	if y < 0 {
+		return y + 1
+	}
+	return y
+}
+
+// ToJD converts Western calendar date to Julian date. The dates skipped by
+// the reform are treated as Julian calendar dates, see Validate.
+func (w WesternCalendar) ToJD(y, m, d int) float64 {
+	y = astronomicalYear(y)
+	if jd := GregorianCalendarToJD(y, m, d); jd >= w.ReformJD {
+		return jd
+	}
+	return JulianCalendarToJD(y, m, d)
+}
+
+// FromJD converts Julian date to Western calendar date.
+func (w WesternCalendar) FromJD(jd float64) (year, month, day int) {
+	if jd >= w.ReformJD {
+		year, month, day, _ = JDToGregorianCalendar(jd)
+	} else {
+		year, month, day, _ = JDToJulianCalendar(jd)
+	}
+	if year <= 0 {
+		year--
+	}
+	return
+}
+
+// IsLeapYear returns true if year y is a leap year. The leap rule of the
+// reform year follows its February.
+func (w WesternCalendar) IsLeapYear(y int) bool {
+	a := astronomicalYear(y)
+	if GregorianCalendarToJD(a, 3, 1) >= w.ReformJD {
+		return LeapYearGregorian(a)
+	}
+	return LeapYearJulian(a)
+}
+
+// Validate checks the date, the year 0 and the days skipped by the reform do
+// not exist.
+func (w WesternCalendar) Validate(y, m, d int) error {
+	if y == 0 {
+		return &DateError{"Western", y, m, d, ErrYearZero}
+	}
+	a := astronomicalYear(y)
+	if GregorianCalendarToJD(a, m, d) >= w.ReformJD {
+		return validateMonthDay("Western", y, m, d, LeapYearGregorian(a))
+	}
+	if err := validateMonthDay("Western", y, m, d, LeapYearJulian(a)); err != nil {
+		return err
+	}
+	if JulianCalendarToJD(a, m, d) >= w.ReformJD {
+		return &DateError{"Western", y, m, d, ErrNonexistentDate}
+	}
+	return nil
+}
+
+// ToGonghe converts Western calendar date to Gonghe calendar date.
+func (w WesternCalendar) ToGonghe(y, m, d int) (year, month, day int) {
+	year, month, day, _ = JDToGongheCalendar(w.ToJD(y, m, d))
+	return
+}
+
+// FromGonghe converts Gonghe calendar date to Western calendar date.
+func (w WesternCalendar) FromGonghe(y, m, d int) (year, month, day int) {
+	return w.FromJD(GongheCalendarToJD(y, m, d))
+}
+
+// ToGHC converts Western calendar date to Gonghe calendar date with
+// 128-leap-rule.
+func (w WesternCalendar) ToGHC(y, m, d int) (year, month, day int) {
+	year, month, day, _ = JDToGHC(w.ToJD(y, m, d))
+	return
+}
+
+// FromGHC converts Gonghe calendar date with 128-leap-rule to Western
+// calendar date.
+func (w WesternCalendar) FromGHC(y, m, d int) (year, month, day int) {
+	return w.FromJD(GHCToJD(y, m, d))
+}
+
+// StemBranch returns the stem-branch of the day.
+func (w WesternCalendar) StemBranch(y, m, d int) string {
+	return JDToStemBranch(w.ToJD(y, m, d))
+}
@@ -0,0 +1,99 @@
+package zcal_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "github.com/tzengyuxio/zcal"
+)
+
+func TestWesternCalendarReform(t *testing.T) {
+	for _, pair := range []struct {
+		country    string
+		last, next [3]int // the last Julian date and the first Gregorian date
+	}{
+		{"IT", [3]int{1582, 10, 4}, [3]int{1582, 10, 15}},
+		{"FR", [3]int{1582, 12, 9}, [3]int{1582, 12, 20}},
+		{"DK", [3]int{1700, 2, 18}, [3]int{1700, 3, 1}},
+		{"GB", [3]int{1752, 9, 2}, [3]int{1752, 9, 14}},
+		{"SE", [3]int{1753, 2, 17}, [3]int{1753, 3, 1}},
+		{"ru", [3]int{1918, 1, 31}, [3]int{1918, 2, 14}},
+		{"GR", [3]int{1923, 2, 15}, [3]int{1923, 3, 1}},
+	} {
+		w, ok := WesternCalendarOfCountry(pair.country)
+		assert.True(t, ok, "For country %s", pair.country)
+		jd := w.ToJD(pair.last[0], pair.last[1], pair.last[2])
+		assert.Equal(t, jd+1, w.ToJD(pair.next[0], pair.next[1], pair.next[2]), "For country %s", pair.country)
+		assert.Equal(t, jd+1, w.ReformJD, "For country %s", pair.country)
+
+		y, m, d := w.FromJD(jd)
+		assert.Equal(t, pair.last, [3]int{y, m, d}, "For country %s", pair.country)
+		y, m, d = w.FromJD(jd + 1)
+		assert.Equal(t, pair.next, [3]int{y, m, d}, "For country %s", pair.country)
+
+		assert.NoError(t, w.Validate(pair.last[0], pair.last[1], pair.last[2]))
+		assert.NoError(t, w.Validate(pair.next[0], pair.next[1], pair.next[2]))
+		y, m, d = w.FromJD(jd - 10)
+		assert.NoError(t, w.Validate(y, m, d))
+	}
+
+	_, ok := WesternCalendarOfCountry("XX")
+	assert.False(t, ok)
+	assert.Equal(t, DefaultWesternCalendar, func() WesternCalendar { w, _ := WesternCalendarOfCountry("IT"); return w }())
+}
+
+func TestWesternCalendarValidate(t *testing.T) {
+	gb, _ := WesternCalendarOfCountry("GB")
+	for _, pair := range []struct {
+		w       WesternCalendar
+		y, m, d int
+		err     error
+	}{
+		{gb, 1752, 9, 3, ErrNonexistentDate},
+		{gb, 1752, 9, 13, ErrNonexistentDate},
+		{gb, 1700, 2, 29, nil},
+		{DefaultWesternCalendar, 1700, 2, 29, ErrInvalidDay},
+		{gb, 1582, 10, 10, nil},
+		{gb, 0, 1, 1, ErrYearZero},
+		{ProlepticGregorianCalendar, 1582, 10, 10, nil},
+		{ProlepticGregorianCalendar, 1500, 2, 29, ErrInvalidDay},
+		{ProlepticJulianCalendar, 1900, 2, 29, nil},
+		{ProlepticJulianCalendar, 2017, 13, 1, ErrInvalidMonth},
+	} {
+		err := pair.w.Validate(pair.y, pair.m, pair.d)
+		if pair.err == nil {
+			assert.NoError(t, err, "For date %04d-%02d-%02d", pair.y, pair.m, pair.d)
+		} else {
+			assert.True(t, errors.Is(err, pair.err), "For date %04d-%02d-%02d expected %v got %v", pair.y, pair.m, pair.d, pair.err, err)
+		}
+	}
+
+	assert.True(t, gb.IsLeapYear(1700))
+	assert.False(t, DefaultWesternCalendar.IsLeapYear(1700))
+	assert.True(t, DefaultWesternCalendar.IsLeapYear(-1))
+	assert.False(t, gb.IsLeapYear(1800))
+}
+
+func TestWesternCalendarConversion(t *testing.T) {
+	gb, _ := WesternCalendarOfCountry("GB")
+
+	// 1750-03-01 in Britain is 1750-03-12 in Gregorian calendar
+	y, m, d := gb.ToGonghe(1750, 3, 1)
+	gy, gm, gd := WesternCalendarToGongheCalendar(1750, 3, 12)
+	assert.Equal(t, [3]int{gy, gm, gd}, [3]int{y, m, d})
+	y, m, d = gb.FromGonghe(gy, gm, gd)
+	assert.Equal(t, [3]int{1750, 3, 1}, [3]int{y, m, d})
+	assert.Equal(t, WesternCalendarToStemBranch(1750, 3, 12), gb.StemBranch(1750, 3, 1))
+
+	y, m, d = gb.ToGHC(1750, 3, 1)
+	hy, hm, hd := WesternCalendarToGHC(1750, 3, 12)
+	assert.Equal(t, [3]int{hy, hm, hd}, [3]int{y, m, d})
+
+	// 共和元年立春
+	assert.Equal(t, JDOfGongheFirstDay, ProlepticJulianCalendar.ToJD(-841, 2, 12))
+	assert.Equal(t, JDOfGongheFirstDay, DefaultWesternCalendar.ToJD(-841, 2, 12))
+	y, m, d = ProlepticGregorianCalendar.FromJD(JDOfGongheFirstDay)
+	assert.Equal(t, [3]int{-841, 2, 4}, [3]int{y, m, d})
+	assert.Equal(t, 2451544.5, ProlepticJulianCalendar.ToJD(1999, 12, 19))
+}
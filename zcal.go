@@ -15,6 +15,7 @@ var JDOfGongheZeroDay = 1413559.5 // 1413559.760417
 // JDOfShuodanDongzhi 為西曆 1384年，洪武十七年，朔旦冬至甲子日
 var JDOfShuodanDongzhi = 2226910.5
 
+// jdOfGregorianCalendar is 1582-10-15, the first day of Gregorian calendar.
 var jdOfGregorianCalendar = 2299160.5
 
 func depart(n float64) (int, float64) {
@@ -51,17 +52,7 @@ func WesternYearToStemBranch(n int) string {
 // WesternCalendarToStemBranch calculates the corresponding stem-branch with
 // the given western year, month and day of month
 func WesternCalendarToStemBranch(y, m, d int) string {
-	var jd float64
-	if y > 1582 || (y == 1582 && m >= 10) {
-		jd = GregorianCalendarToJD(y, m, d)
-	} else {
-		if y < 0 {
-			y++
-		}
-		jd = JulianCalendarToJD(y, m, d)
-	}
-	days := int(math.Floor(jd - JDOfShuodanDongzhi))
-	return StemBranch(days)
+	return DefaultWesternCalendar.StemBranch(y, m, d)
 }
 
 // GregorianCalendarToJD converts Gregorian calendar date to Julian date.
@@ -112,6 +103,18 @@ func JDToJulianCalendar(jd float64) (y, m, d int, t float64) {
 	return
 }
 
+// LeapYearGregorian returns true if year y (astronomical year numbering) is a
+// leap year in Gregorian calendar.
+func LeapYearGregorian(y int) bool {
+	return (y%4 == 0 && y%100 != 0) || y%400 == 0
+}
+
+// LeapYearJulian returns true if year y (astronomical year numbering) is a
+// leap year in Julian calendar.
+func LeapYearJulian(y int) bool {
+	return y%4 == 0
+}
+
 // LeapYearGonghe returns true if year y in the Gonghe year is a leap year
 func LeapYearGonghe(y int) bool {
 	return (y%4 == 0 && y%100 != 0) || y%500 == 0
@@ -198,15 +201,33 @@ func JDToGHC(jd float64) (y, m, d int, t float64) {
 	return
 }
 
+// GHCToJD converts Gonghe calendar date with 128-leap-rule to Julian date, it
+// is the inverse of JDToGHC. Year 0 is the year before year 1, and the days
+// are counted from JDOfGongheZeroDay, which is GHC 0-01-01.
+func GHCToJD(year, month, day int) float64 {
+	y, m, d := year, month-1, day-1
+	gdn := y*365 + floorDiv(y, 4) - floorDiv(y, 128)
+	gdn += m*30 + m/2
+	gdn += d
+
+	return float64(gdn) + JDOfGongheZeroDay
+}
+
+// floorDiv returns a/b rounded toward negative infinity.
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
 // GongheCalendarToJD converts Gonghe calendar date to Julian date.
 func GongheCalendarToJD(year, month, day int) float64 {
 	y, m, d := year-1, month-1, day-1
-	gdn := y*365 + y/4 - y/100 + y/500
+	gdn := y*365 + floorDiv(y, 4) - floorDiv(y, 100) + floorDiv(y, 500)
 	gdn += m*30 + m/2
 	gdn += d
-	if y < 0 {
-		gdn--
-	}
 
 	return float64(gdn) + JDOfGongheFirstDay
 }
@@ -214,46 +235,23 @@ func GongheCalendarToJD(year, month, day int) float64 {
 // GongheCalendarToWesternCalendar converts Gonghe calendar date go Western
 // calendar date.
 func GongheCalendarToWesternCalendar(y, m, d int) (year, month, day int) {
-	jd := GongheCalendarToJD(y, m, d)
-	if jd >= jdOfGregorianCalendar {
-		year, month, day, _ = JDToGregorianCalendar(jd)
-	} else {
-		year, month, day, _ = JDToJulianCalendar(jd)
-		if year <= 0 {
-			year--
-		}
-	}
-	return
+	return DefaultWesternCalendar.FromGonghe(y, m, d)
 }
 
 // WesternCalendarToGongheCalendar converts Western calendar date to Gonghe
 // calendar date.
 func WesternCalendarToGongheCalendar(y, m, d int) (year, month, day int) {
-	var jd float64
-	if y > 1582 || (y == 1582 && m >= 10) {
-		jd = GregorianCalendarToJD(y, m, d)
-	} else {
-		if y < 0 {
-			y++
-		}
-		jd = JulianCalendarToJD(y, m, d)
-	}
-	year, month, day, _ = JDToGongheCalendar(jd)
-	return
+	return DefaultWesternCalendar.ToGonghe(y, m, d)
+}
+
+// GHCToWesternCalendar converts Gonghe calendar date with 128-leap-rule to
+// Western calendar date.
+func GHCToWesternCalendar(y, m, d int) (year, month, day int) {
+	return DefaultWesternCalendar.FromGHC(y, m, d)
 }
 
 // WesternCalendarToGHC converts Western calendar date to Gonghe
 // calendar date.
 func WesternCalendarToGHC(y, m, d int) (year, month, day int) {
-	var jd float64
-	if y > 1582 || (y == 1582 && m >= 10) {
-		jd = GregorianCalendarToJD(y, m, d)
-	} else {
-		if y < 0 {
-			y++
-		}
-		jd = JulianCalendarToJD(y, m, d)
-	}
-	year, month, day, _ = JDToGHC(jd)
-	return
+	return DefaultWesternCalendar.ToGHC(y, m, d)
 }
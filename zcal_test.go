@@ -179,8 +179,12 @@ func TestGongheCalendarToJD(t *testing.T) {
 		y, m, d int
 		jd      float64
 	}{
+		{-499, 1, 1, JDOfGongheFirstDay - 182621.0},
+		{-99, 1, 1, JDOfGongheFirstDay - 36525.0},
 		{-4, 4, 4, JDOfGongheFirstDay - 1733.0},
+		{-3, 1, 1, JDOfGongheFirstDay - 1461.0},
 		{-2, 2, 2, JDOfGongheFirstDay - 1065.0},
+		{-2, 1, 1, JDOfGongheFirstDay - 1096.0},
 		{-1, 1, 1, JDOfGongheFirstDay - 731.0},
 		{-1, 12, 30, JDOfGongheFirstDay - 367.0},
 		{0, 1, 1, JDOfGongheFirstDay - 366.0},
@@ -205,6 +209,22 @@ func TestGongheCalendarToJD(t *testing.T) {
 	}
 }
 
+func TestGongheCalendarRoundTrip(t *testing.T) {
+	for _, y := range []int{-1, -3, -4, -100, -400, -500, -501, -1000, -2999, 0, 1, 100, 500, 2858} {
+		days := 365
+		if LeapYearGonghe(y) {
+			days = 366
+		}
+		jd0 := GongheCalendarToJD(y, 1, 1)
+		assert.Equal(t, float64(days), GongheCalendarToJD(y+1, 1, 1)-jd0, "For year %d", y)
+		for i := 0; i < days; i++ {
+			gy, gm, gd, _ := JDToGongheCalendar(jd0 + float64(i))
+			assert.Equal(t, jd0+float64(i), GongheCalendarToJD(gy, gm, gd), "For year %d day %d", y, i)
+			assert.Equal(t, y, gy, "For year %d day %d", y, i)
+		}
+	}
+}
+
 func TestGCalToWCal(t *testing.T) {
 	for _, pair := range []struct {
 		gy, gm, gd int
@@ -264,3 +284,72 @@ func TestWCalToGHCal(t *testing.T) {
 		// assert.Equal(t, pair.gd, d, "For wcal date %04d-%02d-%02d expected day %d got %d", pair.wy, pair.wm, pair.wd, pair.gd, d)
 	}
 }
+
+func TestGHCToJD(t *testing.T) {
+	for _, pair := range []struct {
+		y, m, d int
+		jd      float64
+	}{
+		{0, 1, 1, JDOfGongheZeroDay},
+		{0, 12, 30, JDOfGongheZeroDay + 364.0}, // GHC 0 is a common year
+		{1, 1, 1, JDOfGongheZeroDay + 365.0},
+		{3, 12, 31, JDOfGongheZeroDay + 1460.0},
+		{-1, 12, 30, JDOfGongheZeroDay - 1.0},
+		{-1, 1, 1, JDOfGongheZeroDay - 365.0},
+		{127, 12, 30, JDOfGongheZeroDay + 46750.0},
+		{128, 1, 1, JDOfGongheZeroDay + 46751.0},
+		{-128, 1, 1, JDOfGongheZeroDay - 46751.0},
+	} {
+		jd := GHCToJD(pair.y, pair.m, pair.d)
+		assert.Equal(t, pair.jd, jd, "For date %04d-%02d-%02d expected %.1f got %.1f", pair.y, pair.m, pair.d, pair.jd, jd)
+	}
+}
+
+func TestGHCRoundTrip(t *testing.T) {
+	// walk every day of GHC years -3000 to 3000, both directions must agree
+	y, m, d := -3000, 1, 1
+	jd := GHCToJD(y, m, d)
+	for y <= 3000 {
+		if got := GHCToJD(y, m, d); got != jd {
+			t.Fatalf("GHCToJD(%d, %d, %d) = %.1f, expected %.1f", y, m, d, got, jd)
+		}
+		gy, gm, gd, _ := JDToGHC(jd + .25)
+		if gy != y || gm != m || gd != d {
+			t.Fatalf("JDToGHC(%.2f) = %d-%02d-%02d, expected %d-%02d-%02d", jd+.25, gy, gm, gd, y, m, d)
+		}
+		jd++
+		if d++; d > NewGHCDate(y, m, 1).DaysInMonth() {
+			y, m = addGHCMonth(y, m)
+			d = 1
+		}
+	}
+}
+
+func addGHCMonth(y, m int) (int, int) {
+	if m == 12 {
+		return y + 1, 1
+	}
+	return y, m + 1
+}
+
+func TestGHCToWesternCalendar(t *testing.T) {
+	for _, pair := range []struct {
+		gy, gm, gd int
+		wy, wm, wd int
+	}{
+		{0, 1, 1, -843, 2, 12},
+		{1, 1, 1, -842, 2, 12},
+		{2841, 11, 28, 2000, 1, 1},
+	} {
+		y, m, d := GHCToWesternCalendar(pair.gy, pair.gm, pair.gd)
+		assert.Equal(t, [3]int{pair.wy, pair.wm, pair.wd}, [3]int{y, m, d}, "For GHC date %04d-%02d-%02d", pair.gy, pair.gm, pair.gd)
+		y, m, d = WesternCalendarToGHC(pair.wy, pair.wm, pair.wd)
+		assert.Equal(t, [3]int{pair.gy, pair.gm, pair.gd}, [3]int{y, m, d}, "For wcal date %04d-%02d-%02d", pair.wy, pair.wm, pair.wd)
+	}
+	for jd := GregorianCalendarToJD(-4000, 1, 1); jd < GregorianCalendarToJD(4000, 1, 1); jd += 13 {
+		wy, wm, wd := DefaultWesternCalendar.FromJD(jd)
+		gy, gm, gd := WesternCalendarToGHC(wy, wm, wd)
+		y, m, d := GHCToWesternCalendar(gy, gm, gd)
+		assert.Equal(t, [3]int{wy, wm, wd}, [3]int{y, m, d})
+	}
+}